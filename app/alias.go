@@ -0,0 +1,175 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"go.bbkane.com/enventory/db/sqlcgen"
+	"go.bbkane.com/enventory/models"
+)
+
+func (e *EnvService) aliasFindID(ctx context.Context, envName string, name string) (int64, error) {
+	queries := sqlcgen.New(e.dbtx)
+
+	envID, err := e.envFindID(ctx, envName)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := queries.AliasFindID(ctx, sqlcgen.AliasFindIDParams{
+		EnvID: envID,
+		Name:  name,
+	})
+	if err != nil {
+		return 0, models.ErrAliasNotFound
+	}
+	return id, nil
+}
+
+func (e *EnvService) AliasCreate(ctx context.Context, args models.AliasCreateArgs) (*models.Alias, error) {
+	queries := sqlcgen.New(e.dbtx)
+
+	envID, err := e.envFindID(ctx, args.EnvName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = queries.AliasCreate(ctx, sqlcgen.AliasCreateParams{
+		EnvID:      envID,
+		Name:       args.Name,
+		Kind:       string(args.Kind),
+		Value:      args.Value,
+		Comment:    args.Comment,
+		CreateTime: models.TimeToString(args.CreateTime),
+		UpdateTime: models.TimeToString(args.UpdateTime),
+		Enabled:    models.BoolToInt64(args.Enabled),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create alias: %w", err)
+	}
+	return &models.Alias{
+		EnvName:    args.EnvName,
+		Name:       args.Name,
+		Kind:       args.Kind,
+		Value:      args.Value,
+		Comment:    args.Comment,
+		CreateTime: args.CreateTime,
+		UpdateTime: args.UpdateTime,
+		Enabled:    args.Enabled,
+	}, nil
+}
+
+func (e *EnvService) AliasDelete(ctx context.Context, envName string, name string) error {
+	queries := sqlcgen.New(e.dbtx)
+
+	envID, err := e.envFindID(ctx, envName)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := queries.AliasDelete(ctx, sqlcgen.AliasDeleteParams{
+		EnvID: envID,
+		Name:  name,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete alias: %s: %s: %w", envName, name, err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrAliasNotFound
+	}
+	return nil
+}
+
+func (e *EnvService) AliasList(ctx context.Context, envName string) ([]models.Alias, error) {
+	queries := sqlcgen.New(e.dbtx)
+
+	envID, err := e.envFindID(ctx, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queries.AliasList(ctx, envID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list aliases: %s: %w", envName, err)
+	}
+	ret := make([]models.Alias, 0, len(rows))
+	for _, row := range rows {
+		ret = append(ret, aliasFromRow(envName, row))
+	}
+	return ret, nil
+}
+
+func (e *EnvService) AliasShow(ctx context.Context, envName string, name string) (*models.Alias, error) {
+	queries := sqlcgen.New(e.dbtx)
+
+	envID, err := e.envFindID(ctx, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := queries.AliasShow(ctx, sqlcgen.AliasShowParams{
+		EnvID: envID,
+		Name:  name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not find alias: %s: %s: %w", envName, name, models.ErrAliasNotFound)
+	}
+	alias := aliasFromRow(envName, row)
+	return &alias, nil
+}
+
+func (e *EnvService) AliasUpdate(ctx context.Context, envName string, name string, args models.AliasUpdateArgs) error {
+	aliasID, err := e.aliasFindID(ctx, envName, name)
+	if err != nil {
+		return err
+	}
+
+	var newEnvID *int64
+	if args.EnvName != nil {
+		tmp, err := e.envFindID(ctx, *args.EnvName)
+		if err != nil {
+			return err
+		}
+		newEnvID = &tmp
+	}
+
+	var kind *string
+	if args.Kind != nil {
+		tmp := string(*args.Kind)
+		kind = &tmp
+	}
+
+	queries := sqlcgen.New(e.dbtx)
+
+	rowsAffected, err := queries.AliasUpdate(ctx, sqlcgen.AliasUpdateParams{
+		EnvID:      newEnvID,
+		Name:       args.Name,
+		Kind:       kind,
+		Value:      args.Value,
+		Comment:    args.Comment,
+		CreateTime: models.TimePtrToStringPtr(args.CreateTime),
+		UpdateTime: models.TimePtrToStringPtr(args.UpdateTime),
+		Enabled:    models.BoolPtrToInt64Ptr(args.Enabled),
+		AliasID:    aliasID,
+	})
+	if err != nil {
+		return fmt.Errorf("err updating alias: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrAliasNotFound
+	}
+	return nil
+}
+
+func aliasFromRow(envName string, row sqlcgen.Alias) models.Alias {
+	return models.Alias{
+		EnvName:    envName,
+		Name:       row.Name,
+		Kind:       models.AliasKind(row.Kind),
+		Value:      row.Value,
+		Comment:    row.Comment,
+		CreateTime: models.StringToTimeMust(row.CreateTime),
+		UpdateTime: models.StringToTimeMust(row.UpdateTime),
+		Enabled:    models.Int64ToBool(row.Enabled),
+	}
+}
@@ -6,7 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/expr-lang/expr"
 	"github.com/xhit/go-str2duration/v2"
@@ -60,6 +64,9 @@ func (e *EnvService) EnvCreate(ctx context.Context, args models.EnvCreateArgs) (
 		CreateTime: models.StringToTimeMust(createdEnvRow.CreateTime),
 		UpdateTime: models.StringToTimeMust(createdEnvRow.UpdateTime),
 		Enabled:    models.Int64ToBool(createdEnvRow.Enabled),
+		// new envs don't have hooks
+		OnEnter: "",
+		OnLeave: "",
 	}, nil
 }
 
@@ -76,7 +83,16 @@ func (e *EnvService) EnvDelete(ctx context.Context, name string) error {
 	return nil
 }
 
+// pathExists reports whether path exists. A leading ~ is expanded to the home
+// directory, since that's how env names store paths under it.
 func pathExists(path string) (bool, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return false, fmt.Errorf("could not get home dir: %w", err)
+		}
+		path = filepath.Join(home, path[1:])
+	}
 	_, err := os.Stat(path)
 	if errors.Is(err, fs.ErrNotExist) {
 		return false, nil
@@ -95,6 +111,15 @@ func (e *EnvService) EnvList(ctx context.Context, args models.EnvListArgs) ([]mo
 		return nil, err
 	}
 
+	repoRows, err := queries.EnvRepoListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list env repos: %w", err)
+	}
+	repos := map[string][]string{}
+	for _, r := range repoRows {
+		repos[r.EnvName] = append(repos[r.EnvName], r.Repo)
+	}
+
 	ret := []models.Env{}
 	for _, e := range sqlcEnvs {
 		ret = append(ret, models.Env{
@@ -103,6 +128,9 @@ func (e *EnvService) EnvList(ctx context.Context, args models.EnvListArgs) ([]mo
 			CreateTime: models.StringToTimeMust(e.CreateTime),
 			UpdateTime: models.StringToTimeMust(e.UpdateTime),
 			Enabled:    models.Int64ToBool(e.Enabled),
+			Repos:      repos[e.Name],
+			OnEnter:    e.OnEnter,
+			OnLeave:    e.OnLeave,
 		})
 	}
 
@@ -166,6 +194,8 @@ func (e *EnvService) EnvUpdate(ctx context.Context, name string, args models.Env
 		CreateTime: models.TimePtrToStringPtr(args.CreateTime),
 		UpdateTime: models.TimePtrToStringPtr(args.UpdateTime),
 		Enabled:    models.BoolPtrToInt64Ptr(args.Enabled),
+		OnEnter:    args.OnEnter,
+		OnLeave:    args.OnLeave,
 		Name:       name,
 	})
 
@@ -188,16 +218,39 @@ func (e *EnvService) EnvShow(ctx context.Context, name string) (*models.Env, err
 		return nil, mapErrEnvNotFound(err)
 	}
 
+	envID, err := e.envFindID(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	repos, err := queries.EnvRepoList(ctx, envID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list env repos: %s: %w", name, err)
+	}
+
 	return &models.Env{
 		Name:       name,
 		Comment:    sqlcEnv.Comment,
 		CreateTime: models.StringToTimeMust(sqlcEnv.CreateTime),
 		UpdateTime: models.StringToTimeMust(sqlcEnv.UpdateTime),
 		Enabled:    models.Int64ToBool(sqlcEnv.Enabled),
+		Repos:      repos,
+		OnEnter:    sqlcEnv.OnEnter,
+		OnLeave:    sqlcEnv.OnLeave,
 	}, nil
 }
 
 func (e *EnvService) EnvExportableList(ctx context.Context, envName string) ([]models.EnvExportable, error) {
+	return e.envExportableList(ctx, envName, nil)
+}
+
+// envExportableList lists envName's exportables followed by the ones it
+// inherits. includedBy holds the chain of envs that included envName and is
+// used to detect cycles.
+func (e *EnvService) envExportableList(ctx context.Context, envName string, includedBy []string) ([]models.EnvExportable, error) {
+	if slices.Contains(includedBy, envName) {
+		return nil, fmt.Errorf("%w: %s", models.ErrEnvIncludeCycle, strings.Join(append(includedBy, envName), " -> "))
+	}
+
 	envID, err := e.envFindID(ctx, envName)
 	if err != nil {
 		return nil, err
@@ -212,11 +265,45 @@ func (e *EnvService) EnvExportableList(ctx context.Context, envName string) ([]m
 	ret := make([]models.EnvExportable, 0, len(rows))
 	for _, row := range rows {
 		ret = append(ret, models.EnvExportable{
-			Name:    row.Name,
-			Enabled: models.Int64ToBool(row.Enabled),
-			Value:   row.Value,
+			Name:       row.Name,
+			Type:       row.Type,
+			Enabled:    models.Int64ToBool(row.Enabled),
+			Value:      row.Value,
+			Scope:      models.JSONToStringSlice(row.Scope),
+			EnvName:    envName,
+			Kind:       models.VarKind(row.Kind),
+			CacheTTL:   models.SecondsToDuration(row.CacheTtl),
+			VarEnvName: row.VarEnvName,
+			VarName:    row.VarName,
 		})
 	}
 
+	includes, err := queries.EnvIncludeList(ctx, envID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list env includes: %s: %w", envName, err)
+	}
+
+	// later includes override earlier ones, and the env's own exportables
+	// override all of them
+	inherited := map[string]models.EnvExportable{}
+	for _, include := range includes {
+		if !models.Int64ToBool(include.IncludeEnvEnabled) {
+			continue
+		}
+		includedExportables, err := e.envExportableList(ctx, include.IncludeEnvName, slices.Concat(includedBy, []string{envName}))
+		if err != nil {
+			return nil, err
+		}
+		for _, ie := range includedExportables {
+			inherited[ie.Name] = ie
+		}
+	}
+	for _, own := range ret {
+		delete(inherited, own.Name)
+	}
+	for _, name := range slices.Sorted(maps.Keys(inherited)) {
+		ret = append(ret, inherited[name])
+	}
+
 	return ret, nil
 }
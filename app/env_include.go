@@ -0,0 +1,128 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.bbkane.com/enventory/db/sqlcgen"
+	"go.bbkane.com/enventory/models"
+)
+
+func (e *EnvService) EnvIncludeCreate(ctx context.Context, envName string, includeEnvName string) error {
+	queries := sqlcgen.New(e.dbtx)
+
+	envID, err := e.envFindID(ctx, envName)
+	if err != nil {
+		return err
+	}
+
+	includeEnvID, err := e.envFindID(ctx, includeEnvName)
+	if err != nil {
+		return err
+	}
+
+	cycle, err := e.envIncludePath(ctx, includeEnvName, envName)
+	if err != nil {
+		return err
+	}
+	if cycle != nil {
+		return fmt.Errorf("%w: %s", models.ErrEnvIncludeCycle, strings.Join(append([]string{envName}, cycle...), " -> "))
+	}
+
+	includes, err := queries.EnvIncludeList(ctx, envID)
+	if err != nil {
+		return fmt.Errorf("could not list env includes: %s: %w", envName, err)
+	}
+	position := int64(0)
+	if len(includes) > 0 {
+		position = includes[len(includes)-1].Position + 1
+	}
+
+	err = queries.EnvIncludeCreate(ctx, sqlcgen.EnvIncludeCreateParams{
+		EnvID:        envID,
+		IncludeEnvID: includeEnvID,
+		Position:     position,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create env include: %s: %s: %w", envName, includeEnvName, err)
+	}
+	return nil
+}
+
+// envIncludePath returns the chain of includes leading from fromEnvName to
+// toEnvName (both included), or nil if fromEnvName doesn't include toEnvName
+// directly or indirectly.
+func (e *EnvService) envIncludePath(ctx context.Context, fromEnvName string, toEnvName string) ([]string, error) {
+	if fromEnvName == toEnvName {
+		return []string{toEnvName}, nil
+	}
+
+	fromEnvID, err := e.envFindID(ctx, fromEnvName)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := sqlcgen.New(e.dbtx)
+	includes, err := queries.EnvIncludeList(ctx, fromEnvID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list env includes: %s: %w", fromEnvName, err)
+	}
+	for _, include := range includes {
+		path, err := e.envIncludePath(ctx, include.IncludeEnvName, toEnvName)
+		if err != nil {
+			return nil, err
+		}
+		if path != nil {
+			return slices.Concat([]string{fromEnvName}, path), nil
+		}
+	}
+	return nil, nil
+}
+
+func (e *EnvService) EnvIncludeDelete(ctx context.Context, envName string, includeEnvName string) error {
+	queries := sqlcgen.New(e.dbtx)
+
+	envID, err := e.envFindID(ctx, envName)
+	if err != nil {
+		return err
+	}
+
+	includeEnvID, err := e.envFindID(ctx, includeEnvName)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := queries.EnvIncludeDelete(ctx, sqlcgen.EnvIncludeDeleteParams{
+		EnvID:        envID,
+		IncludeEnvID: includeEnvID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete env include: %s: %s: %w", envName, includeEnvName, err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrEnvIncludeNotFound
+	}
+	return nil
+}
+
+func (e *EnvService) EnvIncludeList(ctx context.Context, envName string) ([]string, error) {
+	queries := sqlcgen.New(e.dbtx)
+
+	envID, err := e.envFindID(ctx, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	includes, err := queries.EnvIncludeList(ctx, envID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list env includes: %s: %w", envName, err)
+	}
+
+	ret := make([]string, 0, len(includes))
+	for _, include := range includes {
+		ret = append(ret, include.IncludeEnvName)
+	}
+	return ret, nil
+}
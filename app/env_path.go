@@ -0,0 +1,90 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go.bbkane.com/enventory/db/sqlcgen"
+	"go.bbkane.com/enventory/models"
+)
+
+func (e *EnvService) EnvPathAdd(ctx context.Context, args models.EnvPath) error {
+	queries := sqlcgen.New(e.dbtx)
+
+	envID, err := e.envFindID(ctx, args.EnvName)
+	if err != nil {
+		return err
+	}
+
+	err = queries.EnvPathCreate(ctx, sqlcgen.EnvPathCreateParams{
+		EnvID: envID,
+		Path:  args.Path,
+	})
+	if err != nil {
+		return fmt.Errorf("could not add env path: %s: %s: %w", args.EnvName, args.Path, err)
+	}
+	return nil
+}
+
+func (e *EnvService) EnvPathRemove(ctx context.Context, path string) error {
+	queries := sqlcgen.New(e.dbtx)
+
+	rowsAffected, err := queries.EnvPathDelete(ctx, path)
+	if err != nil {
+		return fmt.Errorf("could not remove env path: %s: %w", path, err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrEnvPathNotFound
+	}
+	return nil
+}
+
+func (e *EnvService) EnvPathFind(ctx context.Context, path string) (string, error) {
+	queries := sqlcgen.New(e.dbtx)
+
+	envName, err := queries.EnvPathFindEnvName(ctx, path)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = models.ErrEnvPathNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not find env for path: %s: %w", path, err)
+	}
+	return envName, nil
+}
+
+func (e *EnvService) EnvPathList(ctx context.Context, envName string) ([]models.EnvPath, error) {
+	queries := sqlcgen.New(e.dbtx)
+
+	envID, err := e.envFindID(ctx, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := queries.EnvPathList(ctx, envID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list env paths: %s: %w", envName, err)
+	}
+
+	ret := make([]models.EnvPath, 0, len(paths))
+	for _, path := range paths {
+		ret = append(ret, models.EnvPath{EnvName: envName, Path: path})
+	}
+	return ret, nil
+}
+
+func (e *EnvService) EnvPathListAll(ctx context.Context) ([]models.EnvPath, error) {
+	queries := sqlcgen.New(e.dbtx)
+
+	rows, err := queries.EnvPathListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list env paths: %w", err)
+	}
+
+	ret := make([]models.EnvPath, 0, len(rows))
+	for _, row := range rows {
+		ret = append(ret, models.EnvPath{EnvName: row.EnvName, Path: row.Path})
+	}
+	return ret, nil
+}
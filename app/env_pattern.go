@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.bbkane.com/enventory/db/sqlcgen"
+	"go.bbkane.com/enventory/models"
+)
+
+// compileEnvPattern returns a func reporting whether a path matches pattern.
+// Patterns starting with ~/ aren't expanded; they match paths spelled
+// relative to the home directory, like env names.
+func compileEnvPattern(pattern string, kind models.EnvPatternKind, caseInsensitive bool) (func(path string) bool, error) {
+	switch kind {
+	case models.EnvPatternKindGlob:
+		if caseInsensitive {
+			pattern = strings.ToLower(pattern)
+		}
+		return func(path string) bool {
+			if caseInsensitive {
+				path = strings.ToLower(path)
+			}
+			// malformed globs never match
+			matched, _ := filepath.Match(pattern, path)
+			return matched
+		}, nil
+	case models.EnvPatternKindRegex:
+		flags := ""
+		if caseInsensitive {
+			flags = "(?i)"
+		}
+		re, err := regexp.Compile(flags + "^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %s: %w", pattern, err)
+		}
+		return re.MatchString, nil
+	default:
+		return nil, errors.New("unknown env pattern kind: " + string(kind))
+	}
+}
+
+func (e *EnvService) EnvPatternCreate(ctx context.Context, args models.EnvPattern) error {
+	queries := sqlcgen.New(e.dbtx)
+
+	envID, err := e.envFindID(ctx, args.EnvName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := compileEnvPattern(args.Pattern, args.Kind, false); err != nil {
+		return err
+	}
+
+	err = queries.EnvPatternCreate(ctx, sqlcgen.EnvPatternCreateParams{
+		EnvID:   envID,
+		Pattern: args.Pattern,
+		Kind:    string(args.Kind),
+	})
+	if err != nil {
+		return fmt.Errorf("could not create env pattern: %s: %s: %w", args.EnvName, args.Pattern, err)
+	}
+	return nil
+}
+
+func (e *EnvService) EnvPatternDelete(ctx context.Context, envName string, pattern string) error {
+	queries := sqlcgen.New(e.dbtx)
+
+	envID, err := e.envFindID(ctx, envName)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := queries.EnvPatternDelete(ctx, sqlcgen.EnvPatternDeleteParams{
+		EnvID:   envID,
+		Pattern: pattern,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete env pattern: %s: %s: %w", envName, pattern, err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrEnvPatternNotFound
+	}
+	return nil
+}
+
+func (e *EnvService) EnvPatternList(ctx context.Context, envName string) ([]models.EnvPattern, error) {
+	queries := sqlcgen.New(e.dbtx)
+
+	envID, err := e.envFindID(ctx, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queries.EnvPatternList(ctx, envID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list env patterns: %s: %w", envName, err)
+	}
+
+	ret := make([]models.EnvPattern, 0, len(rows))
+	for _, row := range rows {
+		ret = append(ret, models.EnvPattern{
+			EnvName: envName,
+			Pattern: row.Pattern,
+			Kind:    models.EnvPatternKind(row.Kind),
+		})
+	}
+	return ret, nil
+}
+
+// EnvPatternMatch orders matches so longer (usually more specific) patterns
+// take precedence, breaking ties by env name and then pattern.
+func (e *EnvService) EnvPatternMatch(ctx context.Context, args models.EnvPatternMatchArgs) ([]models.EnvPattern, error) {
+	queries := sqlcgen.New(e.dbtx)
+
+	rows, err := queries.EnvPatternListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list env patterns: %w", err)
+	}
+
+	ret := []models.EnvPattern{}
+	for _, row := range rows {
+		match, err := compileEnvPattern(row.Pattern, models.EnvPatternKind(row.Kind), args.CaseInsensitive)
+		if err != nil {
+			return nil, fmt.Errorf("could not compile pattern for env: %s: %w", row.EnvName, err)
+		}
+		if slices.ContainsFunc(args.Paths, match) {
+			ret = append(ret, models.EnvPattern{
+				EnvName: row.EnvName,
+				Pattern: row.Pattern,
+				Kind:    models.EnvPatternKind(row.Kind),
+			})
+		}
+	}
+	slices.SortFunc(ret, func(a, b models.EnvPattern) int {
+		if c := len(a.Pattern) - len(b.Pattern); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.EnvName, b.EnvName); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Pattern, b.Pattern)
+	})
+	return ret, nil
+}
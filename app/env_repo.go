@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go.bbkane.com/enventory/db/sqlcgen"
+	"go.bbkane.com/enventory/models"
+)
+
+func (e *EnvService) EnvRepoAdd(ctx context.Context, args models.EnvRepo) error {
+	queries := sqlcgen.New(e.dbtx)
+
+	envID, err := e.envFindID(ctx, args.EnvName)
+	if err != nil {
+		return err
+	}
+
+	err = queries.EnvRepoCreate(ctx, sqlcgen.EnvRepoCreateParams{
+		EnvID: envID,
+		Repo:  args.Repo,
+	})
+	if err != nil {
+		return fmt.Errorf("could not add env repo: %s: %s: %w", args.EnvName, args.Repo, err)
+	}
+	return nil
+}
+
+func (e *EnvService) EnvRepoRemove(ctx context.Context, repo string) error {
+	queries := sqlcgen.New(e.dbtx)
+
+	rowsAffected, err := queries.EnvRepoDelete(ctx, repo)
+	if err != nil {
+		return fmt.Errorf("could not remove env repo: %s: %w", repo, err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrEnvRepoNotFound
+	}
+	return nil
+}
+
+func (e *EnvService) EnvRepoFind(ctx context.Context, repo string) (string, error) {
+	queries := sqlcgen.New(e.dbtx)
+
+	envName, err := queries.EnvRepoFindEnvName(ctx, repo)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = models.ErrEnvRepoNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not find env for repo: %s: %w", repo, err)
+	}
+	return envName, nil
+}
@@ -27,6 +27,9 @@ func (e *EnvService) varFindByID(ctx context.Context, id int64) (*models.Var, er
 		Value:       sqlcVar.Value,
 		Enabled:     models.Int64ToBool(sqlcVar.Enabled),
 		Completions: models.JSONToStringSlice(sqlcVar.Completions),
+		Scope:       models.JSONToStringSlice(sqlcVar.Scope),
+		Kind:        models.VarKind(sqlcVar.Kind),
+		CacheTTL:    models.SecondsToDuration(sqlcVar.CacheTtl),
 	}, nil
 }
 
@@ -58,6 +61,12 @@ func (e *EnvService) VarCreate(ctx context.Context, args models.VarCreateArgs) (
 		return nil, err
 	}
 
+	// the zero value is an ordinary var
+	kind := args.Kind
+	if kind == "" {
+		kind = models.VarKindValue
+	}
+
 	err = queries.VarCreate(ctx, sqlcgen.VarCreateParams{
 		EnvID:       envID,
 		Name:        args.Name,
@@ -67,6 +76,9 @@ func (e *EnvService) VarCreate(ctx context.Context, args models.VarCreateArgs) (
 		Value:       args.Value,
 		Enabled:     models.BoolToInt64(args.Enabled),
 		Completions: models.StringSliceToJSON(args.Completions),
+		Scope:       models.StringSliceToJSON(args.Scope),
+		Kind:        string(kind),
+		CacheTtl:    models.DurationToSeconds(args.CacheTTL),
 	})
 
 	if err != nil {
@@ -81,6 +93,9 @@ func (e *EnvService) VarCreate(ctx context.Context, args models.VarCreateArgs) (
 		Value:       args.Value,
 		Enabled:     args.Enabled,
 		Completions: args.Completions,
+		Scope:       args.Scope,
+		Kind:        kind,
+		CacheTTL:    args.CacheTTL,
 	}, nil
 }
 
@@ -128,6 +143,9 @@ func (e *EnvService) VarList(ctx context.Context, envName string) ([]models.Var,
 			Value:       sqlcEnv.Value,
 			Enabled:     models.Int64ToBool(sqlcEnv.Enabled),
 			Completions: models.JSONToStringSlice(sqlcEnv.Completions),
+			Scope:       models.JSONToStringSlice(sqlcEnv.Scope),
+			Kind:        models.VarKind(sqlcEnv.Kind),
+			CacheTTL:    models.SecondsToDuration(sqlcEnv.CacheTtl),
 		})
 	}
 
@@ -178,6 +196,9 @@ func (e *EnvService) VarShow(ctx context.Context, envName string, name string) (
 		Value:       sqlEnvLocalVar.Value,
 		Enabled:     models.Int64ToBool(sqlEnvLocalVar.Enabled),
 		Completions: models.JSONToStringSlice(sqlEnvLocalVar.Completions),
+		Scope:       models.JSONToStringSlice(sqlEnvLocalVar.Scope),
+		Kind:        models.VarKind(sqlEnvLocalVar.Kind),
+		CacheTTL:    models.SecondsToDuration(sqlEnvLocalVar.CacheTtl),
 	}, envRefs, nil
 }
 
@@ -207,6 +228,9 @@ func (e *EnvService) VarUpdate(ctx context.Context, envName string, name string,
 		Value:       args.Value,
 		Enabled:     models.BoolPtrToInt64Ptr(args.Enabled),
 		Completions: models.StringSlicePtrToJSONPtr(args.Completions),
+		Scope:       models.StringSlicePtrToJSONPtr(args.Scope),
+		Kind:        (*string)(args.Kind),
+		CacheTtl:    models.DurationPtrToSecondsPtr(args.CacheTTL),
 		VarID:       envVarID,
 	})
 
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go.bbkane.com/enventory/db/sqlcgen"
+	"go.bbkane.com/enventory/models"
+)
+
+func (e *EnvService) VarCacheShow(ctx context.Context, envName string, name string) (*models.VarCache, error) {
+	varID, err := e.varFindID(ctx, envName, name)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := sqlcgen.New(e.dbtx)
+	row, err := queries.VarCacheShow(ctx, varID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrVarCacheNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not show var cache: %s: %s: %w", envName, name, err)
+	}
+
+	return &models.VarCache{
+		Command:   row.Command,
+		Value:     row.Value,
+		CacheTime: models.StringToTimeMust(row.CacheTime),
+	}, nil
+}
+
+func (e *EnvService) VarCacheSet(ctx context.Context, envName string, name string, cache models.VarCache) error {
+	varID, err := e.varFindID(ctx, envName, name)
+	if err != nil {
+		return err
+	}
+
+	queries := sqlcgen.New(e.dbtx)
+	_, err = queries.VarCacheSet(ctx, sqlcgen.VarCacheSetParams{
+		VarID:     varID,
+		Command:   cache.Command,
+		Value:     cache.Value,
+		CacheTime: models.TimeToString(cache.CacheTime),
+	})
+	if err != nil {
+		return fmt.Errorf("could not set var cache: %s: %s: %w", envName, name, err)
+	}
+	return nil
+}
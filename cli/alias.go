@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"go.bbkane.com/enventory/cli/tableprint"
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/completion"
+	"go.bbkane.com/warg/value/scalar"
+)
+
+// validateAliasName checks that name is a plain command name, since the
+// shell scripts define aliases and functions with it
+func validateAliasName(name string) error {
+	if !commandName.MatchString(name) {
+		return fmt.Errorf("alias name must be a command name: %q", name)
+	}
+	return nil
+}
+
+func completeExistingAliasName(
+	ctx context.Context, es models.Service, cmdCtx warg.CmdContext) (*completion.Candidates, error) {
+	// no completions if we can't get the env name
+	envNamePtr := ptrFromMap[string](cmdCtx.Flags, "--env")
+	if envNamePtr == nil {
+		return nil, nil
+	}
+
+	aliases, err := es.AliasList(ctx, *envNamePtr)
+	if err != nil {
+		return nil, fmt.Errorf("could not get env for completion: %w", err)
+	}
+	candidates := &completion.Candidates{
+		Type:   completion.Type_ValuesDescriptions,
+		Values: nil,
+	}
+	for _, a := range aliases {
+		candidates.Values = append(candidates.Values, completion.Candidate{
+			Name:        a.Name,
+			Description: a.Comment,
+		})
+	}
+	return candidates, nil
+}
+
+func aliasNameFlag() warg.Flag {
+	return warg.NewFlag(
+		"Alias name",
+		scalar.String(),
+		warg.Required(),
+		warg.FlagCompletions(withEnvServiceCompletions(
+			completeExistingAliasName)),
+	)
+}
+
+func AliasCreateCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Create a shell alias or function defined while this env is active",
+		withSetup(aliasCreateRun),
+		warg.CmdFlag(
+			"--env",
+			envNameFlag(),
+		),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+		warg.CmdFlagMap(commonCreateFlagMap()),
+		warg.NewCmdFlag(
+			"--name",
+			"New alias name",
+			scalar.String(),
+			warg.Required(),
+		),
+		warg.NewCmdFlag(
+			"--value",
+			"What the alias expands to, or the body of the function",
+			scalar.String(),
+			warg.Required(),
+		),
+		warg.NewCmdFlag(
+			"--kind",
+			"alias expands to --value. function runs --value, so it can use its arguments",
+			scalar.String(
+				scalar.Choices(string(models.AliasKindAlias), string(models.AliasKindFunction)),
+				scalar.Default(string(models.AliasKindAlias)),
+			),
+			warg.Required(),
+		),
+	)
+}
+
+func aliasCreateRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	commonCreateArgs := mustGetCommonCreateArgs(cmdCtx.Flags)
+
+	envName := envNameArg(cmdCtx, "--env")
+	name := mustGetNameArg(cmdCtx.Flags)
+	if err := validateAliasName(name); err != nil {
+		return err
+	}
+	value := cmdCtx.Flags["--value"].(string)
+	kind := models.AliasKind(cmdCtx.Flags["--kind"].(string))
+
+	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		_, err := es.AliasCreate(
+			ctx,
+			models.AliasCreateArgs{
+				EnvName:    envName,
+				Name:       name,
+				Kind:       kind,
+				Value:      value,
+				Comment:    commonCreateArgs.Comment,
+				CreateTime: commonCreateArgs.CreateTime,
+				UpdateTime: commonCreateArgs.UpdateTime,
+				Enabled:    commonCreateArgs.Enabled,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("couldn't create alias: %s: %w", name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmdCtx.Stdout, "Created alias: %s: %s\n", envName, name)
+	return nil
+}
+
+func AliasDeleteCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Delete a shell alias or function",
+		withConfirm(withSetup(aliasDeleteRun)),
+		warg.CmdFlagMap(confirmFlag()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+		warg.CmdFlag("--name", aliasNameFlag()),
+		warg.CmdFlag(
+			"--env",
+			envNameFlag(),
+		),
+	)
+}
+
+func aliasDeleteRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	envName := envNameArg(cmdCtx, "--env")
+	name := mustGetNameArg(cmdCtx.Flags)
+
+	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		return es.AliasDelete(ctx, envName, name)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmdCtx.Stdout, "Deleted %s: %s\n", envName, name)
+	return nil
+}
+
+func AliasShowCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Show details for a shell alias or function",
+		withSetup(aliasShowRun),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+		warg.CmdFlagMap(timeZoneFlagMap()),
+		warg.CmdFlagMap(widthFlag()),
+		warg.CmdFlag("--name", aliasNameFlag()),
+		warg.CmdFlag(
+			"--env",
+			envNameFlag(),
+		),
+	)
+}
+
+func aliasShowRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	envName := envNameArg(cmdCtx, "--env")
+	name := mustGetNameArg(cmdCtx.Flags)
+
+	alias, err := es.AliasShow(ctx, envName, name)
+	if err != nil {
+		return fmt.Errorf("couldn't find alias: %s: %w", name, err)
+	}
+
+	c := tableprint.CommonTablePrintArgs{
+		Format:          tableprint.Format_Table,
+		Mask:            false,
+		Tz:              tableprint.Timezone(mustGetTimezoneArg(cmdCtx.Flags)),
+		W:               cmdCtx.Stdout,
+		DesiredMaxWidth: mustGetWidthArg(cmdCtx.Flags),
+	}
+	tableprint.AliasShowPrint(c, *alias)
+	return nil
+}
+
+func AliasUpdateCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Update a shell alias or function",
+		withConfirm(withSetup(aliasUpdateRun)),
+		warg.CmdFlag("--env", envNameFlag()),
+		warg.CmdFlagMap(commonUpdateFlags()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+		warg.CmdFlagMap(confirmFlag()),
+		warg.CmdFlag("--name", aliasNameFlag()),
+		warg.NewCmdFlag(
+			"--new-env",
+			"New env name",
+			scalar.String(),
+			warg.FlagCompletions(withEnvServiceCompletions(
+				completeExistingEnvName)),
+		),
+		warg.NewCmdFlag(
+			"--value",
+			"New expansion or function body",
+			scalar.String(),
+		),
+		warg.NewCmdFlag(
+			"--kind",
+			"New kind",
+			scalar.String(
+				scalar.Choices(string(models.AliasKindAlias), string(models.AliasKindFunction)),
+			),
+		),
+	)
+}
+
+func aliasUpdateRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	commonUpdateArgs := getCommonUpdateArgs(cmdCtx.Flags)
+
+	envName := envNameArg(cmdCtx, "--env")
+	name := mustGetNameArg(cmdCtx.Flags)
+	newEnvName := envNameArgPtr(cmdCtx, "--new-env")
+	value := ptrFromMap[string](cmdCtx.Flags, "--value")
+	var kind *models.AliasKind
+	if k, exists := cmdCtx.Flags["--kind"]; exists {
+		tmp := models.AliasKind(k.(string))
+		kind = &tmp
+	}
+	if commonUpdateArgs.NewName != nil {
+		if err := validateAliasName(*commonUpdateArgs.NewName); err != nil {
+			return err
+		}
+	}
+
+	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		err := es.AliasUpdate(ctx, envName, name, models.AliasUpdateArgs{
+			Comment:    commonUpdateArgs.Comment,
+			CreateTime: commonUpdateArgs.CreateTime,
+			EnvName:    newEnvName,
+			Kind:       kind,
+			Name:       commonUpdateArgs.NewName,
+			UpdateTime: commonUpdateArgs.UpdateTime,
+			Value:      value,
+			Enabled:    commonUpdateArgs.Enabled,
+		})
+		if err != nil {
+			return fmt.Errorf("could not update alias: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	finalName := name
+	if commonUpdateArgs.NewName != nil {
+		finalName = *commonUpdateArgs.NewName
+	}
+	finalEnvName := envName
+	if newEnvName != nil {
+		finalEnvName = *newEnvName
+	}
+	fmt.Fprintf(cmdCtx.Stdout, "updated alias: %s: %s\n", finalEnvName, finalName)
+	return nil
+}
@@ -463,10 +463,6 @@ func getCommonUpdateArgs(pf warg.PassedFlags) commonUpdateArgs {
 	}
 }
 
-func mustGetEnvNameArg(pf warg.PassedFlags) string {
-	return pf["--env"].(string)
-}
-
 func mustGetMaskArg(pf warg.PassedFlags) bool {
 	return pf["--mask"].(bool)
 }
@@ -566,21 +562,31 @@ func withEnvServiceCompletions(
 // withConfirm wraps a cli.Action to ask for confirmation before running
 func withConfirm(f func(cmdCtx warg.CmdContext) error) warg.Action {
 	return func(cmdCtx warg.CmdContext) error {
-		confirm := cmdCtx.Flags["--confirm"].(bool)
-		if !confirm {
-			return f(cmdCtx)
-		}
-
-		fmt.Print("Type 'yes' to continue: ")
-		reader := bufio.NewReader(os.Stdin)
-		confirmation, err := reader.ReadString('\n')
+		err := askConfirmation(cmdCtx)
 		if err != nil {
-			return fmt.Errorf("confirmation ReadString error: %w", err)
-		}
-		confirmation = strings.TrimSpace(confirmation)
-		if confirmation != "yes" {
-			return fmt.Errorf("unconfirmed change")
+			return err
 		}
 		return f(cmdCtx)
 	}
 }
+
+// askConfirmation asks the user to type 'yes' if --confirm is true. Use it
+// instead of withConfirm to show what will change before asking.
+func askConfirmation(cmdCtx warg.CmdContext) error {
+	confirm := cmdCtx.Flags["--confirm"].(bool)
+	if !confirm {
+		return nil
+	}
+
+	fmt.Print("Type 'yes' to continue: ")
+	reader := bufio.NewReader(os.Stdin)
+	confirmation, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("confirmation ReadString error: %w", err)
+	}
+	confirmation = strings.TrimSpace(confirmation)
+	if confirmation != "yes" {
+		return fmt.Errorf("unconfirmed change")
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package cli
+
+import (
+	_ "embed"
+
+	"go.bbkane.com/warg"
+)
+
+//go:embed completion_script.bash
+var bashCompletionScript []byte
+
+func CompletionBashCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Print bash completion script",
+		func(ctx warg.CmdContext) error {
+			_, err := ctx.Stdout.Write(bashCompletionScript)
+			return err
+		},
+	)
+}
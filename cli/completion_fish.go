@@ -0,0 +1,20 @@
+package cli
+
+import (
+	_ "embed"
+
+	"go.bbkane.com/warg"
+)
+
+//go:embed completion_script.fish
+var fishCompletionScript []byte
+
+func CompletionFishCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Print fish completion script",
+		func(ctx warg.CmdContext) error {
+			_, err := ctx.Stdout.Write(fishCompletionScript)
+			return err
+		},
+	)
+}
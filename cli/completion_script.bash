@@ -0,0 +1,58 @@
+# bash completion for enventory, export-env, unexport-env, and push-env
+
+_enventory() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    COMPREPLY=()
+
+    local -a output
+    case "${COMP_WORDS[0]}" in
+        export-env | unexport-env | push-env)
+            mapfile -t output < <(enventory --completion-zsh shell zsh export --env '')
+            ;;
+        *)
+            mapfile -t output < <("${COMP_WORDS[0]}" --completion-zsh "${COMP_WORDS[@]:1:COMP_CWORD}")
+            ;;
+    esac
+
+    # Check if we got any output
+    [[ ${#output[@]} -eq 0 ]] && return 1
+
+    # First line is the type
+    local comp_type="${output[0]}"
+
+    local i
+    case "$comp_type" in
+        COMPLETION_TYPE_DIRECTORIES)
+            compopt -o filenames
+            mapfile -t COMPREPLY < <(compgen -d -- "$cur")
+            ;;
+
+        COMPLETION_TYPE_DIRECTORIES_FILES)
+            compopt -o filenames
+            mapfile -t COMPREPLY < <(compgen -f -- "$cur")
+            ;;
+
+        COMPLETION_TYPE_NONE)
+            return 0
+            ;;
+
+        COMPLETION_TYPE_VALUES)
+            for ((i = 1; i < ${#output[@]}; i++)); do
+                [[ "${output[i]}" == "$cur"* ]] && COMPREPLY+=("${output[i]}")
+            done
+            ;;
+
+        COMPLETION_TYPE_VALUES_DESCRIPTIONS)
+            # bash can't display descriptions, so skip them
+            for ((i = 1; i < ${#output[@]}; i += 2)); do
+                [[ "${output[i]}" == "$cur"* ]] && COMPREPLY+=("${output[i]}")
+            done
+            ;;
+
+        *)
+            return 1
+            ;;
+    esac
+}
+
+complete -F _enventory enventory export-env unexport-env push-env
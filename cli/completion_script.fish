@@ -0,0 +1,46 @@
+# fish completion for enventory, export-env, unexport-env, and push-env
+
+function __enventory_complete
+    set -l tokens (commandline -opc)
+    set -l current (commandline -ct)
+    set -l cmd $tokens[1]
+    set -e tokens[1]
+
+    switch $cmd
+        case export-env unexport-env push-env
+            set output (enventory --completion-zsh shell zsh export --env '')
+        case '*'
+            set output ($cmd --completion-zsh $tokens "$current")
+    end
+
+    # Check if we got any output
+    test (count $output) -eq 0; and return 1
+
+    # First line is the type
+    switch $output[1]
+        case COMPLETION_TYPE_DIRECTORIES
+            __fish_complete_directories "$current"
+
+        case COMPLETION_TYPE_DIRECTORIES_FILES
+            __fish_complete_path "$current"
+
+        case COMPLETION_TYPE_NONE
+            return 0
+
+        case COMPLETION_TYPE_VALUES
+            printf '%s\n' $output[2..-1]
+
+        case COMPLETION_TYPE_VALUES_DESCRIPTIONS
+            for i in (seq 2 2 (count $output))
+                printf '%s\t%s\n' $output[$i] $output[(math $i + 1)]
+            end
+
+        case '*'
+            return 1
+    end
+end
+
+complete -c enventory -f -a '(__enventory_complete)'
+complete -c export-env -f -a '(__enventory_complete)'
+complete -c unexport-env -f -a '(__enventory_complete)'
+complete -c push-env -f -a '(__enventory_complete)'
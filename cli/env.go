@@ -10,6 +10,7 @@ import (
 	"go.bbkane.com/warg"
 
 	"go.bbkane.com/warg/value/scalar"
+	"go.bbkane.com/warg/value/slice"
 )
 
 func EnvCreateCmd() warg.Cmd {
@@ -19,6 +20,13 @@ func EnvCreateCmd() warg.Cmd {
 		withSetup(func(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
 			// Get enabled from flags since it's not using PointerTo
 			createArgs.Enabled = cmdCtx.Flags["--enabled"].(bool)
+			createArgs.Name = canonicalEnvName(cmdCtx, createArgs.Name)
+			extends := []string{}
+			if extendsIFace, exists := cmdCtx.Flags["--extends"]; exists {
+				for _, e := range extendsIFace.([]string) {
+					extends = append(extends, canonicalEnvName(cmdCtx, e))
+				}
+			}
 			var env *models.Env
 			err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
 				var err error
@@ -26,6 +34,12 @@ func EnvCreateCmd() warg.Cmd {
 				if err != nil {
 					return err
 				}
+				for _, includeEnvName := range extends {
+					err = es.EnvIncludeCreate(ctx, env.Name, includeEnvName)
+					if err != nil {
+						return fmt.Errorf("could not include env: %s: %w", includeEnvName, err)
+					}
+				}
 				return nil
 			})
 			if err != nil {
@@ -51,6 +65,12 @@ func EnvCreateCmd() warg.Cmd {
 			),
 			warg.Required(),
 		),
+		warg.NewCmdFlag(
+			"--extends",
+			"Envs to include vars and refs from. Later envs override earlier ones",
+			slice.String(),
+			warg.FlagCompletions(withEnvServiceCompletions(completeExistingEnvName)),
+		),
 	)
 }
 
@@ -66,7 +86,7 @@ func EnvDeleteCmd() warg.Cmd {
 }
 
 func envDelete(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
-	name := mustGetNameArg(cmdCtx.Flags)
+	name := existingEnvNameArg(ctx, es, cmdCtx, "--name")
 	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
 		err := es.EnvDelete(ctx, name)
 		if err != nil {
@@ -148,7 +168,19 @@ func envList(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) err
 		DesiredMaxWidth: mustGetWidthArg(cmdCtx.Flags),
 	}
 
-	tableprint.EnvList(c, envs)
+	ts := newTrustStore(cmdCtx)
+	trusted := map[string]bool{}
+	for _, e := range envs {
+		t, err := envTrusted(ctx, es, ts, e)
+		if err != nil {
+			return err
+		}
+		if t != nil {
+			trusted[e.Name] = *t
+		}
+	}
+
+	tableprint.EnvList(c, envs, trusted)
 	return nil
 }
 
@@ -167,7 +199,7 @@ func EnvShowCmd() warg.Cmd {
 
 func envShow(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
 	mask := mustGetMaskArg(cmdCtx.Flags)
-	name := mustGetNameArg(cmdCtx.Flags)
+	name := existingEnvNameArg(ctx, es, cmdCtx, "--name")
 	timezone := mustGetTimezoneArg(cmdCtx.Flags)
 	width := mustGetWidthArg(cmdCtx.Flags)
 
@@ -175,6 +207,11 @@ func envShow(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) err
 	var localvars []models.Var
 	var refs []models.VarRef
 	var referencedVars []models.Var
+	var aliases []models.Alias
+	var includes []string
+	var inherited []models.EnvExportable
+	var paths []models.EnvPath
+	var patterns []models.EnvPattern
 
 	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
 		var err error
@@ -192,6 +229,36 @@ func envShow(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) err
 		if err != nil {
 			return err
 		}
+
+		aliases, err = es.AliasList(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		includes, err = es.EnvIncludeList(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		paths, err = es.EnvPathList(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		patterns, err = es.EnvPatternList(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		exportables, err := es.EnvExportableList(ctx, name)
+		if err != nil {
+			return err
+		}
+		for _, e := range exportables {
+			if e.EnvName != name {
+				inherited = append(inherited, e)
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -205,7 +272,11 @@ func envShow(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) err
 		W:               cmdCtx.Stdout,
 		DesiredMaxWidth: width,
 	}
-	tableprint.EnvShowRun(c, *env, localvars, refs, referencedVars)
+	trusted, err := envTrusted(ctx, es, newTrustStore(cmdCtx), *env)
+	if err != nil {
+		return err
+	}
+	tableprint.EnvShowRun(c, *env, trusted, localvars, refs, referencedVars, aliases, includes, inherited, paths, patterns)
 	return nil
 }
 
@@ -218,6 +289,16 @@ func EnvUpdateCmd() warg.Cmd {
 		warg.CmdFlagMap(timeoutFlagMap()),
 		warg.CmdFlagMap(sqliteDSNFlagMap()),
 		warg.CmdFlagMap(confirmFlag()),
+		warg.NewCmdFlag(
+			"--on-enter",
+			"Command the shell runs when it enters the env. Empty removes it. Runs once trusted with 'env trust'",
+			scalar.String(),
+		),
+		warg.NewCmdFlag(
+			"--on-leave",
+			"Command the shell runs when it leaves the env. Empty removes it. Runs once trusted with 'env trust'",
+			scalar.String(),
+		),
 	)
 }
 
@@ -225,11 +306,14 @@ func envUpdate(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) e
 	// common update flags
 	comment := ptrFromMap[string](cmdCtx.Flags, "--comment")
 	createTime := ptrFromMap[time.Time](cmdCtx.Flags, "--create-time")
-	newName := ptrFromMap[string](cmdCtx.Flags, "--new-name")
+	newName := envNameArgPtr(cmdCtx, "--new-name")
 	updateTime := ptrFromMap[time.Time](cmdCtx.Flags, "--update-time")
 	enabled := ptrFromMap[bool](cmdCtx.Flags, "--enabled")
 
-	name := mustGetNameArg(cmdCtx.Flags)
+	onEnter := ptrFromMap[string](cmdCtx.Flags, "--on-enter")
+	onLeave := ptrFromMap[string](cmdCtx.Flags, "--on-leave")
+
+	name := existingEnvNameArg(ctx, es, cmdCtx, "--name")
 
 	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
 		err := es.EnvUpdate(ctx, name, models.EnvUpdateArgs{
@@ -238,6 +322,8 @@ func envUpdate(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) e
 			Name:       newName,
 			UpdateTime: updateTime,
 			Enabled:    enabled,
+			OnEnter:    onEnter,
+			OnLeave:    onLeave,
 		})
 		if err != nil {
 			return fmt.Errorf("could not update env: %w", err)
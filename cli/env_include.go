@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/value/scalar"
+)
+
+func envIncludeFlag() warg.Flag {
+	return warg.NewFlag(
+		"Included environment name",
+		scalar.String(),
+		warg.Required(),
+		warg.FlagCompletions(withEnvServiceCompletions(completeExistingEnvName)),
+	)
+}
+
+func EnvIncludeCreateCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Include another env's vars and refs in this env. Later includes override earlier ones",
+		withSetup(envIncludeCreateRun),
+		warg.CmdFlag("--env", envNameFlag()),
+		warg.CmdFlag("--include", envIncludeFlag()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func envIncludeCreateRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	envName := envNameArg(cmdCtx, "--env")
+	includeEnvName := envNameArg(cmdCtx, "--include")
+
+	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		err := es.EnvIncludeCreate(ctx, envName, includeEnvName)
+		if err != nil {
+			return fmt.Errorf("could not include env: %s: %w", includeEnvName, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmdCtx.Stdout, "Created env include: %s: %s\n", envName, includeEnvName)
+	return nil
+}
+
+func EnvIncludeDeleteCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Stop including another env's vars and refs in this env",
+		withConfirm(withSetup(envIncludeDeleteRun)),
+		warg.CmdFlag("--env", envNameFlag()),
+		warg.CmdFlag("--include", envIncludeFlag()),
+		warg.CmdFlagMap(confirmFlag()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func envIncludeDeleteRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	envName := envNameArg(cmdCtx, "--env")
+	includeEnvName := envNameArg(cmdCtx, "--include")
+
+	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		err := es.EnvIncludeDelete(ctx, envName, includeEnvName)
+		if err != nil {
+			return fmt.Errorf("could not delete env include: %s: %w", includeEnvName, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmdCtx.Stdout, "Deleted env include: %s: %s\n", envName, includeEnvName)
+	return nil
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+)
+
+// This file canonicalizes env names that are paths, so a directory maps to
+// the same env whether it's reached through a symlink, with a trailing slash,
+// or (optionally) with different case. Paths under the home directory are
+// stored relative to it (~/proj), so a database synced between machines with
+// different home directories still works.
+
+// caseInsensitivePathsEnvVar lowercases path env names when set to true. It's
+// read from the environment instead of a flag because every command that
+// takes an env name has to agree on it.
+const caseInsensitivePathsEnvVar = "ENVENTORY_CASE_INSENSITIVE_PATHS"
+
+// pathCanonicalizer turns paths into canonical directories and env names
+type pathCanonicalizer struct {
+	caseInsensitive bool
+	// home is the canonical home directory, or "" if it's unknown
+	home string
+	// rawHome is the home directory as $HOME spells it
+	rawHome string
+}
+
+func newPathCanonicalizer(cmdCtx warg.CmdContext) pathCanonicalizer {
+	pc := pathCanonicalizer{caseInsensitive: false, home: "", rawHome: ""}
+	if val, exists := lookupEnvFromCtx(cmdCtx)(caseInsensitivePathsEnvVar); exists {
+		caseInsensitive, err := strconv.ParseBool(val)
+		pc.caseInsensitive = err == nil && caseInsensitive
+	}
+	// $HOME comes from the shell's environment so it matches the directories
+	// the shell passes in
+	home, exists := lookupEnvFromCtx(cmdCtx)("HOME")
+	if !exists {
+		var err error
+		if home, err = os.UserHomeDir(); err != nil {
+			home = ""
+		}
+	}
+	if filepath.IsAbs(home) {
+		pc.home = pc.canonicalPath(home)
+		pc.rawHome = filepath.Clean(home)
+	}
+	return pc
+}
+
+// isPath reports whether name is a path (as opposed to a plain env name like
+// "work")
+func isPath(name string) bool {
+	return name == "~" || strings.HasPrefix(name, "~/") || filepath.IsAbs(name)
+}
+
+// canonicalPath cleans an absolute path and resolves symlinks. If the path
+// doesn't exist, its longest existing ancestor is resolved instead.
+func (pc pathCanonicalizer) canonicalPath(p string) string {
+	p = filepath.Clean(p)
+	canonical := p
+	rest := ""
+	for current := p; ; current = filepath.Dir(current) {
+		resolved, err := filepath.EvalSymlinks(current)
+		if err == nil {
+			canonical = filepath.Join(resolved, rest)
+			break
+		}
+		if filepath.Dir(current) == current {
+			break
+		}
+		rest = filepath.Join(filepath.Base(current), rest)
+	}
+	if pc.caseInsensitive {
+		canonical = strings.ToLower(canonical)
+	}
+	return canonical
+}
+
+// Dir returns the canonical absolute directory for an absolute or
+// ~-relative path
+func (pc pathCanonicalizer) Dir(p string) string {
+	if pc.home != "" {
+		if p == "~" {
+			p = pc.home
+		} else if rest, found := strings.CutPrefix(p, "~/"); found {
+			p = filepath.Join(pc.home, rest)
+		}
+	}
+	if !filepath.IsAbs(p) {
+		return p
+	}
+	return pc.canonicalPath(p)
+}
+
+// DirEnvName returns the env name for a canonical directory from Dir. It's
+// relative to the home directory if possible.
+func (pc pathCanonicalizer) DirEnvName(dir string) string {
+	if pc.home == "" {
+		return dir
+	}
+	if dir == pc.home {
+		return "~"
+	}
+	if rest, found := strings.CutPrefix(dir, pc.home+string(filepath.Separator)); found {
+		return "~/" + filepath.ToSlash(rest)
+	}
+	return dir
+}
+
+// LexicalEnvName returns the env name for a path that may not be on this
+// machine, like a directory envs were moved away from. It's only cleaned and
+// made relative to the home directory, since resolving symlinks or changing
+// case would describe this machine's filesystem instead.
+func (pc pathCanonicalizer) LexicalEnvName(p string) string {
+	p = filepath.Clean(p)
+	if pc.rawHome != "" && pc.rawHome != pc.home {
+		if p == pc.rawHome {
+			return "~"
+		}
+		if rest, found := strings.CutPrefix(p, pc.rawHome+string(filepath.Separator)); found {
+			return "~/" + filepath.ToSlash(rest)
+		}
+	}
+	return pc.DirEnvName(p)
+}
+
+// EnvName canonicalizes env names that are paths and leaves other names
+// alone
+func (pc pathCanonicalizer) EnvName(name string) string {
+	if !isPath(name) {
+		return name
+	}
+	return pc.DirEnvName(pc.Dir(name))
+}
+
+// canonicalEnvName is pathCanonicalizer.EnvName for a single name
+func canonicalEnvName(cmdCtx warg.CmdContext, name string) string {
+	return newPathCanonicalizer(cmdCtx).EnvName(name)
+}
+
+// envNameArg returns the env name passed to flagName, canonicalized with
+// canonicalEnvName
+func envNameArg(cmdCtx warg.CmdContext, flagName string) string {
+	return canonicalEnvName(cmdCtx, cmdCtx.Flags[flagName].(string))
+}
+
+// envNameArgPtr is envNameArg for optional flags. It returns nil if flagName
+// wasn't passed.
+func envNameArgPtr(cmdCtx warg.CmdContext, flagName string) *string {
+	if _, exists := cmdCtx.Flags[flagName]; !exists {
+		return nil
+	}
+	name := envNameArg(cmdCtx, flagName)
+	return &name
+}
+
+// existingEnvNameArg is envNameArg, but it falls back to the name as passed
+// when only an env with that name exists. This lets envs created before names
+// were canonicalized still be shown, updated, and deleted.
+func existingEnvNameArg(ctx context.Context, es models.Service, cmdCtx warg.CmdContext, flagName string) string {
+	name := envNameArg(cmdCtx, flagName)
+	passed := cmdCtx.Flags[flagName].(string)
+	if name == passed {
+		return name
+	}
+	if _, err := es.EnvShow(ctx, name); errors.Is(err, models.ErrEnvNotFound) {
+		if _, err := es.EnvShow(ctx, passed); err == nil {
+			return passed
+		}
+	}
+	return name
+}
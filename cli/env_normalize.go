@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/value/scalar"
+)
+
+const envNormalizeCmdHelpLong = `Rename envs and env paths to their canonical paths. Older versions of enventory
+stored paths as given, so envs created through a symlink or with a trailing
+slash don't match when enventory looks them up by canonical path.
+
+Names that would collide (e.g. an env named after a symlink and another named
+after its target) are reported and left alone. Merge or rename those by hand and
+run this again.
+
+Set ENVENTORY_CASE_INSENSITIVE_PATHS=true to lowercase paths too.`
+
+func EnvNormalizeCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Rename envs and env paths to their canonical paths",
+		withSetup(envNormalizeRun),
+		warg.CmdHelpLong(envNormalizeCmdHelpLong),
+		warg.NewCmdFlag(
+			"--dry-run",
+			"Print changes without making them",
+			scalar.Bool(
+				scalar.Default(false),
+			),
+			warg.Required(),
+		),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+// nameChange is an env name or env path to rename
+type nameChange struct {
+	Old string
+	New string
+}
+
+// normalizeCollision is a group of names with the same canonical form
+type normalizeCollision struct {
+	Names     []string
+	Canonical string
+}
+
+// planNormalize groups names by canonicalName. Names that are the only one
+// mapping to their canonical name and aren't already canonical are renamed.
+// Groups with more than one name are collisions.
+func planNormalize(names []string, canonicalName func(string) string) ([]nameChange, []normalizeCollision) {
+	canonicals := []string{}
+	groups := map[string][]string{}
+	for _, name := range names {
+		c := canonicalName(name)
+		if _, exists := groups[c]; !exists {
+			canonicals = append(canonicals, c)
+		}
+		groups[c] = append(groups[c], name)
+	}
+
+	renames := []nameChange{}
+	collisions := []normalizeCollision{}
+	for _, c := range canonicals {
+		group := groups[c]
+		switch {
+		case len(group) > 1:
+			collisions = append(collisions, normalizeCollision{Names: group, Canonical: c})
+		case group[0] != c:
+			renames = append(renames, nameChange{Old: group[0], New: c})
+		}
+	}
+	return renames, collisions
+}
+
+func envNormalizeRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	dryRun := cmdCtx.Flags["--dry-run"].(bool)
+	canonicalName := func(name string) string {
+		return canonicalEnvName(cmdCtx, name)
+	}
+
+	var envRenames, pathRenames []nameChange
+	var envCollisions, pathCollisions []normalizeCollision
+	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		envs, err := es.EnvList(ctx, models.EnvListArgs{Expr: nil})
+		if err != nil {
+			return fmt.Errorf("could not list envs: %w", err)
+		}
+		envNames := make([]string, 0, len(envs))
+		for _, e := range envs {
+			envNames = append(envNames, e.Name)
+		}
+		envRenames, envCollisions = planNormalize(envNames, canonicalName)
+
+		envPaths, err := es.EnvPathListAll(ctx)
+		if err != nil {
+			return fmt.Errorf("could not list env paths: %w", err)
+		}
+		paths := make([]string, 0, len(envPaths))
+		envNameByPath := map[string]string{}
+		for _, p := range envPaths {
+			paths = append(paths, p.Path)
+			envNameByPath[p.Path] = p.EnvName
+		}
+		pathRenames, pathCollisions = planNormalize(paths, canonicalName)
+
+		if dryRun {
+			return nil
+		}
+
+		return renameEnvsAndPaths(ctx, es, envRenames, pathRenames, envNameByPath)
+	})
+	if err != nil {
+		return err
+	}
+
+	printNameChanges(cmdCtx.Stdout, dryRun, envRenames, pathRenames)
+	for _, c := range envCollisions {
+		fmt.Fprintf(cmdCtx.Stdout, "Env collision: %s -> %s\n", strings.Join(c.Names, ", "), c.Canonical)
+	}
+	for _, c := range pathCollisions {
+		fmt.Fprintf(cmdCtx.Stdout, "Env path collision: %s -> %s\n", strings.Join(c.Names, ", "), c.Canonical)
+	}
+
+	if collisions := len(envCollisions) + len(pathCollisions); collisions > 0 {
+		return fmt.Errorf("found %d collisions. Merge or rename them by hand and run this again", collisions)
+	}
+	return nil
+}
+
+// renameEnvsAndPaths renames envs, then env paths. envNameByPath maps each env
+// path to its env's name before the envs are renamed.
+func renameEnvsAndPaths(ctx context.Context, es models.Service, envRenames []nameChange, pathRenames []nameChange, envNameByPath map[string]string) error {
+	renamedEnvs := map[string]string{}
+	for _, r := range envRenames {
+		renamedEnvs[r.Old] = r.New
+		err := es.EnvUpdate(ctx, r.Old, models.EnvUpdateArgs{
+			Comment:    nil,
+			CreateTime: nil,
+			Name:       &r.New,
+			UpdateTime: nil,
+			Enabled:    nil,
+			OnEnter:    nil,
+			OnLeave:    nil,
+		})
+		if err != nil {
+			return fmt.Errorf("could not rename env: %s: %w", r.Old, err)
+		}
+	}
+	for _, r := range pathRenames {
+		err := es.EnvPathRemove(ctx, r.Old)
+		if err != nil {
+			return fmt.Errorf("could not remove env path: %s: %w", r.Old, err)
+		}
+		envName := envNameByPath[r.Old]
+		if newName, renamed := renamedEnvs[envName]; renamed {
+			envName = newName
+		}
+		err = es.EnvPathAdd(ctx, models.EnvPath{EnvName: envName, Path: r.New})
+		if err != nil {
+			return fmt.Errorf("could not add env path: %s: %w", r.New, err)
+		}
+	}
+	return nil
+}
+
+func printNameChanges(w io.Writer, dryRun bool, envRenames []nameChange, pathRenames []nameChange) {
+	verb := "Renamed"
+	if dryRun {
+		verb = "Would rename"
+	}
+	for _, r := range envRenames {
+		fmt.Fprintf(w, "%s env: %s -> %s\n", verb, r.Old, r.New)
+	}
+	for _, r := range pathRenames {
+		fmt.Fprintf(w, "%s env path: %s -> %s\n", verb, r.Old, r.New)
+	}
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/path"
+	"go.bbkane.com/warg/value/scalar"
+)
+
+func envPathFlag() warg.Flag {
+	return warg.NewFlag(
+		"Directory path",
+		scalar.Path(
+			scalar.Default(path.New(cwd)),
+		),
+		warg.Required(),
+	)
+}
+
+// getEnvPathArg returns the --path flag as a canonical absolute path
+func getEnvPathArg(cmdCtx warg.CmdContext) (string, error) {
+	p, err := filepath.Abs(cmdCtx.Flags["--path"].(path.Path).MustExpand())
+	if err != nil {
+		return "", fmt.Errorf("could not make path absolute: %w", err)
+	}
+	return canonicalEnvName(cmdCtx, p), nil
+}
+
+func EnvPathAddCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Activate this env in another directory",
+		withSetup(envPathAddRun),
+		warg.CmdFlag("--env", envNameFlag()),
+		warg.CmdFlag("--path", envPathFlag()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func envPathAddRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	envName := envNameArg(cmdCtx, "--env")
+	envPath, err := getEnvPathArg(cmdCtx)
+	if err != nil {
+		return err
+	}
+
+	err = es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		err := es.EnvPathAdd(ctx, models.EnvPath{EnvName: envName, Path: envPath})
+		if err != nil {
+			return fmt.Errorf("could not add env path: %s: %w", envPath, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmdCtx.Stdout, "Added env path: %s: %s\n", envName, envPath)
+	return nil
+}
+
+func EnvPathRemoveCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Stop activating an env in a directory",
+		withConfirm(withSetup(envPathRemoveRun)),
+		warg.CmdFlag("--path", envPathFlag()),
+		warg.CmdFlagMap(confirmFlag()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func envPathRemoveRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	envPath, err := getEnvPathArg(cmdCtx)
+	if err != nil {
+		return err
+	}
+
+	err = es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		err := es.EnvPathRemove(ctx, envPath)
+		if err != nil {
+			return fmt.Errorf("could not remove env path: %s: %w", envPath, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmdCtx.Stdout, "Removed env path: %s\n", envPath)
+	return nil
+}
+
+func EnvPathListCmd() warg.Cmd {
+	return warg.NewCmd(
+		"List the directories an env is activated for besides the one it's named after",
+		withSetup(envPathListRun),
+		warg.CmdFlag("--env", envNameFlag()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func envPathListRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	envName := envNameArg(cmdCtx, "--env")
+
+	paths, err := es.EnvPathList(ctx, envName)
+	if err != nil {
+		return fmt.Errorf("could not list env paths: %s: %w", envName, err)
+	}
+	for _, p := range paths {
+		fmt.Fprintln(cmdCtx.Stdout, p.Path)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/value/scalar"
+)
+
+func envPatternFlag() warg.Flag {
+	return warg.NewFlag(
+		"Path pattern. Patterns starting with ~/ match directories under the home directory",
+		scalar.String(),
+		warg.Required(),
+	)
+}
+
+func EnvPatternCreateCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Activate this env in directories matching a pattern",
+		withSetup(envPatternCreateRun),
+		warg.CmdFlag("--env", envNameFlag()),
+		warg.CmdFlag("--pattern", envPatternFlag()),
+		warg.NewCmdFlag(
+			"--kind",
+			"Pattern kind. Globs match like Go's filepath.Match (* doesn't match /). Regexes must match the whole path. Both ignore case when ENVENTORY_CASE_INSENSITIVE_PATHS=true",
+			scalar.String(
+				scalar.Choices(string(models.EnvPatternKindGlob), string(models.EnvPatternKindRegex)),
+				scalar.Default(string(models.EnvPatternKindGlob)),
+			),
+			warg.Required(),
+		),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func envPatternCreateRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	envName := envNameArg(cmdCtx, "--env")
+	pattern := cmdCtx.Flags["--pattern"].(string)
+	kind := models.EnvPatternKind(cmdCtx.Flags["--kind"].(string))
+
+	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		err := es.EnvPatternCreate(ctx, models.EnvPattern{
+			EnvName: envName,
+			Pattern: pattern,
+			Kind:    kind,
+		})
+		if err != nil {
+			return fmt.Errorf("could not create env pattern: %s: %w", pattern, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmdCtx.Stdout, "Created env pattern: %s: %s\n", envName, pattern)
+	return nil
+}
+
+func EnvPatternDeleteCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Stop activating this env in directories matching a pattern",
+		withConfirm(withSetup(envPatternDeleteRun)),
+		warg.CmdFlag("--env", envNameFlag()),
+		warg.CmdFlag("--pattern", envPatternFlag()),
+		warg.CmdFlagMap(confirmFlag()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func envPatternDeleteRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	envName := envNameArg(cmdCtx, "--env")
+	pattern := cmdCtx.Flags["--pattern"].(string)
+
+	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		err := es.EnvPatternDelete(ctx, envName, pattern)
+		if err != nil {
+			return fmt.Errorf("could not delete env pattern: %s: %w", pattern, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmdCtx.Stdout, "Deleted env pattern: %s: %s\n", envName, pattern)
+	return nil
+}
+
+func EnvMatchCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Print the envs `shell <shell> chdir` activates for a path, from lowest to highest precedence",
+		withSetup(envMatchRun),
+		warg.NewCmdFlag(
+			"--path",
+			"Path to match",
+			scalar.String(
+				scalar.Default(cwd),
+			),
+			warg.Required(),
+		),
+		warg.CmdFlagMap(hierarchicalFlagMap()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func envMatchRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	pc := newPathCanonicalizer(cmdCtx)
+	path := pc.Dir(cmdCtx.Flags["--path"].(string))
+	hierarchical := cmdCtx.Flags["--hierarchical"].(bool)
+
+	envs, err := dirEnvs(ctx, es, pc, path, hierarchical)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range envs {
+		env, err := es.EnvShow(ctx, e.EnvName)
+		if errors.Is(err, models.ErrEnvNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not show env: %s: %w", e.EnvName, err)
+		}
+		disabled := ""
+		if !env.Enabled {
+			disabled = " (disabled)"
+		}
+		fmt.Fprintf(cmdCtx.Stdout, "%s: %s%s\n", e.EnvName, e.Reason, disabled)
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/value/scalar"
+)
+
+const envPruneDefaultExpr = `filter(Envs, (hasPrefix(.Name, "/") or hasPrefix(.Name, "~")) and not pathExists(.Name))`
+
+const envPruneCmdHelpLong = `Delete envs for directories that no longer exist, along with their vars and refs.
+
+Envs are selected with the same expression language as 'env list --expr'. By
+default, that's every env named after a path that doesn't exist.
+
+Envs whose vars are referenced by refs in envs that aren't being pruned, or
+that are included by envs that aren't being pruned, are skipped since
+deleting them would break the other envs.
+
+Examples:
+
+# see what would be pruned
+enventory env prune --dry-run
+
+# also require the env to be older than a year
+enventory env prune --expr 'filter(Envs, hasPrefix(.Name, "~") and not pathExists(.Name) and .UpdateTime < now() - duration("52w"))'`
+
+func EnvPruneCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Delete envs for directories that no longer exist",
+		withSetup(envPruneRun),
+		warg.CmdHelpLong(envPruneCmdHelpLong),
+		warg.NewCmdFlag(
+			"--expr",
+			"Expression selecting envs to prune",
+			scalar.String(
+				scalar.Default(envPruneDefaultExpr),
+			),
+			warg.Required(),
+		),
+		warg.NewCmdFlag(
+			"--dry-run",
+			"Print envs to prune without deleting them",
+			scalar.Bool(
+				scalar.Default(false),
+			),
+			warg.Required(),
+		),
+		warg.CmdFlagMap(confirmFlag()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+// incomingRef is a ref in another env pointing to one of an env's vars
+type incomingRef struct {
+	VarName string
+	Ref     models.VarRef
+}
+
+// pruneCandidate is an env selected for pruning
+type pruneCandidate struct {
+	EnvName      string
+	Vars         []models.Var
+	IncomingRefs []incomingRef
+	// IncludedBy lists envs including this one
+	IncludedBy []string
+	// Blocked is true when envs that won't be pruned depend on this one
+	Blocked bool
+}
+
+// listPruneCandidates finds envs selected by expr, what depends on them, and
+// whether that blocks deleting them
+func listPruneCandidates(ctx context.Context, es models.Service, expr string) ([]pruneCandidate, error) {
+	selected, err := es.EnvList(ctx, models.EnvListArgs{Expr: &expr})
+	if err != nil {
+		return nil, fmt.Errorf("could not select envs: %w", err)
+	}
+
+	allEnvs, err := es.EnvList(ctx, models.EnvListArgs{Expr: nil})
+	if err != nil {
+		return nil, fmt.Errorf("could not list envs: %w", err)
+	}
+	includedBy := map[string][]string{}
+	for _, env := range allEnvs {
+		includes, err := es.EnvIncludeList(ctx, env.Name)
+		if err != nil {
+			return nil, fmt.Errorf("could not list env includes: %s: %w", env.Name, err)
+		}
+		for _, include := range includes {
+			includedBy[include] = append(includedBy[include], env.Name)
+		}
+	}
+
+	candidates := []pruneCandidate{}
+	for _, env := range selected {
+		vars, err := es.VarList(ctx, env.Name)
+		if err != nil {
+			return nil, fmt.Errorf("could not list vars: %s: %w", env.Name, err)
+		}
+		incoming := []incomingRef{}
+		for _, v := range vars {
+			_, refs, err := es.VarShow(ctx, env.Name, v.Name)
+			if err != nil {
+				return nil, fmt.Errorf("could not show var: %s: %s: %w", env.Name, v.Name, err)
+			}
+			for _, r := range refs {
+				incoming = append(incoming, incomingRef{VarName: v.Name, Ref: r})
+			}
+		}
+		candidates = append(candidates, pruneCandidate{
+			EnvName:      env.Name,
+			Vars:         vars,
+			IncomingRefs: incoming,
+			IncludedBy:   includedBy[env.Name],
+			Blocked:      false,
+		})
+	}
+
+	// An env is blocked if an env that won't be pruned depends on it. Blocking
+	// an env can block the envs it depends on, so repeat until nothing changes
+	for changed := true; changed; {
+		changed = false
+		pruned := map[string]bool{}
+		for _, c := range candidates {
+			pruned[c.EnvName] = !c.Blocked
+		}
+		for i, c := range candidates {
+			if c.Blocked {
+				continue
+			}
+			dependents := slices.Clone(c.IncludedBy)
+			for _, r := range c.IncomingRefs {
+				dependents = append(dependents, r.Ref.EnvName)
+			}
+			for _, d := range dependents {
+				if !pruned[d] {
+					candidates[i].Blocked = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return candidates, nil
+}
+
+func envPruneRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	expr := cmdCtx.Flags["--expr"].(string)
+	dryRun := cmdCtx.Flags["--dry-run"].(bool)
+
+	candidates, err := listPruneCandidates(ctx, es, expr)
+	if err != nil {
+		return err
+	}
+
+	toPrune := []string{}
+	for _, c := range candidates {
+		if c.Blocked {
+			fmt.Fprintf(cmdCtx.Stdout, "%s (skipped: other envs depend on it)\n", c.EnvName)
+		} else {
+			fmt.Fprintln(cmdCtx.Stdout, c.EnvName)
+			toPrune = append(toPrune, c.EnvName)
+		}
+		for _, v := range c.Vars {
+			fmt.Fprintf(cmdCtx.Stdout, "  var: %s\n", v.Name)
+		}
+		for _, r := range c.IncomingRefs {
+			fmt.Fprintf(cmdCtx.Stdout, "  var %s referenced by: %s: %s\n", r.VarName, r.Ref.EnvName, r.Ref.Name)
+		}
+		for _, e := range c.IncludedBy {
+			fmt.Fprintf(cmdCtx.Stdout, "  included by: %s\n", e)
+		}
+	}
+
+	if len(toPrune) == 0 {
+		fmt.Fprintln(cmdCtx.Stdout, "no envs to prune")
+		return nil
+	}
+	if dryRun {
+		return nil
+	}
+
+	err = askConfirmation(cmdCtx)
+	if err != nil {
+		return err
+	}
+
+	err = es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		// refs and includes between pruned envs would block deleting the
+		// envs they point to, so remove them first
+		for _, envName := range toPrune {
+			refs, _, err := es.VarRefList(ctx, envName)
+			if err != nil {
+				return fmt.Errorf("could not list refs: %s: %w", envName, err)
+			}
+			for _, r := range refs {
+				err = es.VarRefDelete(ctx, envName, r.Name)
+				if err != nil {
+					return fmt.Errorf("could not delete ref: %s: %s: %w", envName, r.Name, err)
+				}
+			}
+			includes, err := es.EnvIncludeList(ctx, envName)
+			if err != nil {
+				return fmt.Errorf("could not list env includes: %s: %w", envName, err)
+			}
+			for _, include := range includes {
+				err = es.EnvIncludeDelete(ctx, envName, include)
+				if err != nil {
+					return fmt.Errorf("could not delete env include: %s: %s: %w", envName, include, err)
+				}
+			}
+		}
+		for _, envName := range toPrune {
+			err := es.EnvDelete(ctx, envName)
+			if err != nil {
+				return fmt.Errorf("could not delete env: %s: %w", envName, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, envName := range toPrune {
+		fmt.Fprintf(cmdCtx.Stdout, "deleted: %s\n", envName)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/value/scalar"
+)
+
+const envRelocateCmdHelpLong = `Rewrite env names and env paths under one directory to another directory, for
+when a whole tree of projects moved. All renames happen in one transaction, so
+if any new name is taken, nothing changes.
+
+Examples:
+
+# projects moved from ~/src to ~/code
+enventory env relocate --from-prefix ~/src --to-prefix ~/code
+
+# the database came from a machine that stored absolute paths
+enventory env relocate --from-prefix /home/alice --to-prefix ~`
+
+func EnvRelocateCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Move envs and env paths from one directory to another",
+		withSetup(envRelocateRun),
+		warg.CmdHelpLong(envRelocateCmdHelpLong),
+		warg.NewCmdFlag(
+			"--from-prefix",
+			"Directory the envs were under. It's only cleaned and made relative to ~, since it may not exist on this machine",
+			scalar.String(),
+			warg.Required(),
+		),
+		warg.NewCmdFlag(
+			"--to-prefix",
+			"Directory the envs are under now",
+			scalar.String(),
+			warg.Required(),
+		),
+		warg.NewCmdFlag(
+			"--dry-run",
+			"Print changes without making them",
+			scalar.Bool(
+				scalar.Default(false),
+			),
+			warg.Required(),
+		),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+// relocateName replaces the from directory at the start of name with to. It
+// returns false if name isn't from or under it.
+func relocateName(name string, from string, to string) (string, bool) {
+	if name == from {
+		return to, true
+	}
+	rest, found := strings.CutPrefix(name, strings.TrimSuffix(from, "/")+"/")
+	if !found {
+		return "", false
+	}
+	return strings.TrimSuffix(to, "/") + "/" + rest, true
+}
+
+func envRelocateRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	from := newPathCanonicalizer(cmdCtx).LexicalEnvName(cmdCtx.Flags["--from-prefix"].(string))
+	to := envNameArg(cmdCtx, "--to-prefix")
+	dryRun := cmdCtx.Flags["--dry-run"].(bool)
+	if !isPath(from) || !isPath(to) {
+		return fmt.Errorf("--from-prefix and --to-prefix must be absolute or start with ~/: %s, %s", from, to)
+	}
+
+	var envRenames, pathRenames []nameChange
+	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		envs, err := es.EnvList(ctx, models.EnvListArgs{Expr: nil})
+		if err != nil {
+			return fmt.Errorf("could not list envs: %w", err)
+		}
+		for _, e := range envs {
+			if newName, found := relocateName(e.Name, from, to); found {
+				envRenames = append(envRenames, nameChange{Old: e.Name, New: newName})
+			}
+		}
+
+		envPaths, err := es.EnvPathListAll(ctx)
+		if err != nil {
+			return fmt.Errorf("could not list env paths: %w", err)
+		}
+		envNameByPath := map[string]string{}
+		for _, p := range envPaths {
+			envNameByPath[p.Path] = p.EnvName
+			if newPath, found := relocateName(p.Path, from, to); found {
+				pathRenames = append(pathRenames, nameChange{Old: p.Path, New: newPath})
+			}
+		}
+
+		if dryRun {
+			return nil
+		}
+		return renameEnvsAndPaths(ctx, es, envRenames, pathRenames, envNameByPath)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(envRenames) == 0 && len(pathRenames) == 0 {
+		fmt.Fprintf(cmdCtx.Stdout, "No envs or env paths under: %s\n", from)
+		return nil
+	}
+	printNameChanges(cmdCtx.Stdout, dryRun, envRenames, pathRenames)
+	return nil
+}
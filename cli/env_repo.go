@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/value/scalar"
+)
+
+func envRepoFlagMap() warg.FlagMap {
+	return warg.FlagMap{
+		"--repo": warg.NewFlag(
+			"Git remote URL or repo key (like github.com/owner/repo). Defaults to the origin remote of the repo enclosing --path",
+			scalar.String(),
+		),
+		"--path": envPathFlag(),
+	}
+}
+
+// getEnvRepoArg returns the normalized --repo flag, or the key of the repo
+// enclosing --path
+func getEnvRepoArg(cmdCtx warg.CmdContext) (string, error) {
+	if repo, exists := cmdCtx.Flags["--repo"]; exists {
+		key := normalizeGitRemoteURL(repo.(string))
+		if key == "" {
+			return "", errors.New("--repo must not be empty")
+		}
+		return key, nil
+	}
+
+	dir, err := getEnvPathArg(cmdCtx)
+	if err != nil {
+		return "", err
+	}
+	repo, err := findGitRepo(dir)
+	if err != nil {
+		return "", fmt.Errorf("could not find git repo: %s: %w", dir, err)
+	}
+	if repo == nil {
+		return "", fmt.Errorf("not in a git repo: %s", dir)
+	}
+	if repo.Key == "" {
+		return "", fmt.Errorf("git repo has no origin remote: %s", repo.Root)
+	}
+	return repo.Key, nil
+}
+
+func EnvRepoAddCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Activate this env in every clone and worktree of a git repo",
+		withSetup(envRepoAddRun),
+		warg.CmdFlag("--env", envNameFlag()),
+		warg.CmdFlagMap(envRepoFlagMap()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func envRepoAddRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	envName := envNameArg(cmdCtx, "--env")
+	repo, err := getEnvRepoArg(cmdCtx)
+	if err != nil {
+		return err
+	}
+
+	err = es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		err := es.EnvRepoAdd(ctx, models.EnvRepo{EnvName: envName, Repo: repo})
+		if err != nil {
+			return fmt.Errorf("could not add env repo: %s: %w", repo, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmdCtx.Stdout, "Added env repo: %s: %s\n", envName, repo)
+	return nil
+}
+
+func EnvRepoRemoveCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Stop activating an env in a git repo",
+		withConfirm(withSetup(envRepoRemoveRun)),
+		warg.CmdFlagMap(envRepoFlagMap()),
+		warg.CmdFlagMap(confirmFlag()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func envRepoRemoveRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	repo, err := getEnvRepoArg(cmdCtx)
+	if err != nil {
+		return err
+	}
+
+	err = es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		err := es.EnvRepoRemove(ctx, repo)
+		if err != nil {
+			return fmt.Errorf("could not remove env repo: %s: %w", repo, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmdCtx.Stdout, "Removed env repo: %s\n", repo)
+	return nil
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+)
+
+const envTrustCmdHelpLong = `Trust an env's on-enter and on-leave hooks and command vars so they run.
+
+Hooks run arbitrary commands when the shell changes directory, and command vars
+(var create --kind command) run theirs when the env is exported, so they only
+run after being trusted. Trusting records a hash of the env name, hooks, and
+commands, so editing any of them, adding a command var, or renaming the env
+requires trusting the env again. Until then, the shell prints a warning instead
+of running them.
+
+Hashes are kept outside the database, in $ENVENTORY_TRUST_DIR (default
+$XDG_DATA_HOME/enventory/trust or ~/.local/share/enventory/trust), so a
+database edited by someone else or synced from another machine can't trust
+its own code.
+
+Examples:
+
+enventory env update --name . --on-enter 'nvm use' --confirm false
+enventory env trust --name .`
+
+func EnvTrustCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Allow an env's hooks and command vars to run",
+		withSetup(envTrustRun),
+		warg.CmdHelpLong(envTrustCmdHelpLong),
+		warg.CmdFlag("--name", envNameFlag()),
+		warg.CmdFlagMap(confirmFlag()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func envTrustRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	name := existingEnvNameArg(ctx, es, cmdCtx, "--name")
+
+	env, err := es.EnvShow(ctx, name)
+	if err != nil {
+		return fmt.Errorf("could not show env: %s: %w", name, err)
+	}
+	ts := newTrustStore(cmdCtx)
+	code, err := loadEnvCode(ctx, es, *env)
+	if err != nil {
+		return err
+	}
+	if code.Empty() {
+		fmt.Fprintln(cmdCtx.Stdout, "no hooks or command vars to trust:", name)
+		return nil
+	}
+	hash := code.Hash()
+	trusted, err := ts.Trusted(name, hash)
+	if err != nil {
+		return err
+	}
+	if trusted {
+		fmt.Fprintln(cmdCtx.Stdout, "already trusted:", name)
+		return nil
+	}
+
+	// show exactly what's being trusted before asking
+	if env.OnEnter != "" {
+		fmt.Fprintf(cmdCtx.Stdout, "on-enter: %s\n", env.OnEnter)
+	}
+	if env.OnLeave != "" {
+		fmt.Fprintf(cmdCtx.Stdout, "on-leave: %s\n", env.OnLeave)
+	}
+	for _, varName := range slices.Sorted(maps.Keys(code.Commands)) {
+		fmt.Fprintf(cmdCtx.Stdout, "command var %s: %s\n", varName, code.Commands[varName])
+	}
+	err = askConfirmation(cmdCtx)
+	if err != nil {
+		return err
+	}
+
+	err = ts.Trust(name, hash)
+	if err != nil {
+		return fmt.Errorf("could not trust env: %w", err)
+	}
+
+	fmt.Fprintln(cmdCtx.Stdout, "trusted:", name)
+	return nil
+}
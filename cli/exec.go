@@ -220,6 +220,15 @@ func ExecCmd() warg.Cmd {
 				warg.Required(),
 			),
 		),
+		warg.CmdFlag("--scoped",
+			warg.NewFlag(
+				"Also pass the scoped vars for the command that the shell recorded in "+shellExportedVar+". The zsh init script's wrapper functions use this",
+				scalar.Bool(
+					scalar.Default(false),
+				),
+				warg.Required(),
+			),
+		),
 		warg.CmdFlag("--print-vars",
 			warg.NewFlag(
 				"Print the environment variables that would be set before executing the command",
@@ -261,7 +270,9 @@ func execRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) err
 	envs := []string{}
 	envsIFace, exists := cmdCtx.Flags["--env"]
 	if exists {
-		envs = envsIFace.([]string)
+		for _, e := range envsIFace.([]string) {
+			envs = append(envs, canonicalEnvName(cmdCtx, e))
+		}
 	}
 
 	for _, envName := range envs {
@@ -269,8 +280,10 @@ func execRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) err
 		if err != nil {
 			return fmt.Errorf("could not list exportable env vars: %s: %w", envName, err)
 		}
+		exportables, failed := resolveExportableValues(ctx, es, cmdCtx, exportables)
+		warnFailedVars(cmdCtx, failed)
 		for _, ev := range exportables {
-			if ev.Enabled {
+			if ev.Enabled && inScope(ev.Scope, command) {
 				vars = append(vars, kv{
 					Name:  ev.Name,
 					Value: ev.Value,
@@ -279,6 +292,14 @@ func execRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) err
 		}
 	}
 
+	if cmdCtx.Flags["--scoped"].(bool) {
+		scopedVars, err := recordedScopedVars(ctx, cmdCtx, es, command)
+		if err != nil {
+			return err
+		}
+		vars = append(vars, scopedVars...)
+	}
+
 	// set groups
 	// I don't love reading the YAML twice, but this will do for now
 	configPath := os.Getenv("ENVENTORY_EXEC_CONFIG")
@@ -322,6 +343,7 @@ func execRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) err
 		"--help",
 		"--inherit-env",
 		"--print-vars",
+		"--scoped",
 		"--timeout",
 	)
 
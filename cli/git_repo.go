@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// This file finds the git repo enclosing a directory by reading .git
+// directly, so `shell <shell> chdir` doesn't need to run git or touch the
+// network.
+
+// gitRepo is a git repo enclosing a directory
+type gitRepo struct {
+	// Root is the top directory of the worktree
+	Root string
+	// Key identifies the repo across clones and worktrees. It's the
+	// normalized URL of the origin remote, or "" if there is no origin.
+	Key string
+}
+
+// findGitRepo returns the git repo enclosing dir, or nil if there is none
+func findGitRepo(dir string) (*gitRepo, error) {
+	if !filepath.IsAbs(dir) {
+		return nil, nil
+	}
+	for current := filepath.Clean(dir); ; current = filepath.Dir(current) {
+		dotGit := filepath.Join(current, ".git")
+		info, err := os.Stat(dotGit)
+		if err == nil {
+			gitDir := dotGit
+			if !info.IsDir() {
+				// worktrees and submodules have a .git file pointing to the real git dir
+				gitDir, err = readGitDirFile(dotGit)
+				if err != nil {
+					return nil, err
+				}
+			}
+			commonDir, err := gitCommonDir(gitDir)
+			if err != nil {
+				return nil, err
+			}
+			remoteURL, err := gitRemoteURL(filepath.Join(commonDir, "config"), "origin")
+			if err != nil {
+				return nil, err
+			}
+			return &gitRepo{Root: current, Key: normalizeGitRemoteURL(remoteURL)}, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("could not stat: %s: %w", dotGit, err)
+		}
+		if filepath.Dir(current) == current {
+			return nil, nil
+		}
+	}
+}
+
+// readGitDirFile reads a .git file like "gitdir: ../.git/worktrees/name"
+func readGitDirFile(dotGitFile string) (string, error) {
+	content, err := os.ReadFile(dotGitFile)
+	if err != nil {
+		return "", fmt.Errorf("could not read .git file: %w", err)
+	}
+	gitDir, found := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir:")
+	if !found {
+		return "", fmt.Errorf("unexpected .git file contents: %s", dotGitFile)
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(dotGitFile), gitDir)
+	}
+	return gitDir, nil
+}
+
+// gitCommonDir returns the git dir holding the config shared by all worktrees
+func gitCommonDir(gitDir string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return gitDir, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not read commondir: %w", err)
+	}
+	commonDir := strings.TrimSpace(string(content))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(gitDir, commonDir)
+	}
+	return commonDir, nil
+}
+
+// gitRemoteURL reads the URL of a remote from a git config file. It returns ""
+// if the remote or config doesn't exist. It only understands the subset of
+// git config syntax that git itself writes for remotes.
+func gitRemoteURL(configPath string, remote string) (string, error) {
+	f, err := os.Open(configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not open git config: %w", err)
+	}
+	defer f.Close()
+
+	wantSection := fmt.Sprintf(`remote "%s"`, remote)
+	inSection := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			section := strings.TrimSuffix(strings.TrimPrefix(line, "["), "]")
+			inSection = section == wantSection
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found || !strings.EqualFold(strings.TrimSpace(key), "url") {
+			continue
+		}
+		return unquoteGitConfigValue(strings.TrimSpace(value)), nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("could not read git config: %w", err)
+	}
+	return "", nil
+}
+
+func unquoteGitConfigValue(value string) string {
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return value
+	}
+	return strings.ReplaceAll(value[1:len(value)-1], `\"`, `"`)
+}
+
+// normalizeGitRemoteURL turns the different ways to write a remote URL into
+// the same key, so https and ssh clones of a repo match. For example,
+// "git@github.com:bbkane/enventory.git" and
+// "https://github.com/bbkane/enventory" both become
+// "github.com/bbkane/enventory".
+func normalizeGitRemoteURL(remoteURL string) string {
+	remoteURL = strings.TrimSpace(remoteURL)
+	if remoteURL == "" {
+		return ""
+	}
+
+	var host, repoPath string
+	// single letter schemes are Windows drive letters
+	if u, err := url.Parse(remoteURL); err == nil && len(u.Scheme) > 1 {
+		host = u.Hostname()
+		repoPath = u.Path
+	} else if userHost, p, found := strings.Cut(remoteURL, ":"); found && !strings.Contains(userHost, "/") && len(userHost) > 1 {
+		// scp-like syntax: [user@]host:path
+		_, host, _ = strings.Cut(userHost, "@")
+		if host == "" {
+			host = userHost
+		}
+		repoPath = p
+	} else {
+		repoPath = remoteURL
+	}
+
+	repoPath = strings.TrimSuffix(strings.TrimSuffix(repoPath, "/"), ".git")
+	if host == "" {
+		return filepath.Clean(repoPath)
+	}
+	return strings.ToLower(host) + "/" + strings.TrimPrefix(repoPath, "/")
+}
@@ -0,0 +1,444 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+
+	"go.bbkane.com/warg/value/scalar"
+)
+
+// This file holds the shell-independent parts of the `shell <shell>` commands.
+// Each shell passes its shellDialect to write the final script.
+
+func noEnvNoProblemFlagMap() warg.FlagMap {
+	return warg.FlagMap{
+		"--no-env-no-problem": warg.NewFlag(
+			"Exit without an error if the environment doesn't exit. Useful when runnng envelop on chpwd",
+			scalar.Bool(
+				scalar.Default(false),
+			),
+			warg.Required(),
+		),
+	}
+}
+
+// shellExportCmd builds the export command. Shells pass opts to add flags
+// only they support.
+func shellExportCmd(d shellDialect, opts ...warg.CmdOpt) warg.Cmd {
+	return warg.NewCmd(
+		"Print export script",
+		withSetup(func(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+			return shellExportUnexport(ctx, cmdCtx, es, d, "export")
+		}),
+		slices.Concat([]warg.CmdOpt{
+			warg.CmdFlag("--env", shellEnvNameFlag()),
+			warg.CmdFlagMap(timeoutFlagMap()),
+			warg.CmdFlagMap(sqliteDSNFlagMap()),
+			warg.CmdFlagMap(noEnvNoProblemFlagMap()),
+		}, opts)...,
+	)
+}
+
+func shellUnexportCmd(d shellDialect) warg.Cmd {
+	return warg.NewCmd(
+		"Print unexport script",
+		withSetup(func(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+			return shellExportUnexport(ctx, cmdCtx, es, d, "unexport")
+		}),
+		warg.CmdFlag("--env", shellEnvNameFlag()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+		warg.CmdFlagMap(noEnvNoProblemFlagMap()),
+	)
+}
+
+func shellExportUnexport(ctx context.Context, cmdCtx warg.CmdContext, es models.Service, d shellDialect, scriptType string) error {
+	envName := envNameArg(cmdCtx, "--env")
+	noEnvNoProblem := cmdCtx.Flags["--no-env-no-problem"].(bool)
+	lookupEnv := lookupEnvFromCtx(cmdCtx)
+
+	// keep an unknown envName as is - the shell might have recorded
+	// exporting it before it was deleted
+	resolvedEnvName, err := resolveEnvName(ctx, es, envName)
+	if err != nil && !errors.Is(err, models.ErrEnvNotFound) {
+		return err
+	}
+	if err == nil {
+		envName = resolvedEnvName
+	}
+
+	exported, tracked := readExported(lookupEnv)
+	before := maps.Clone(exported)
+	aliases := readAliases(lookupEnv)
+
+	// unexport what the shell recorded exporting instead of what the env
+	// holds now, so vars deleted or renamed since export still get unset
+	if scriptType == "unexport" && tracked {
+		changes := []shellChange{}
+		for _, name := range exported.namesInEnv(envName) {
+			if exported[name].Scope == "" {
+				changes = append(changes, shellChange{Op: shellChangeOpRemove, Name: name, Value: "", Silent: false})
+			}
+			delete(exported, name)
+		}
+		changes = append(changes, aliasTransition(aliases, []string{envName}, nil)...)
+		if !maps.EqualFunc(before, exported, exportedVar.equal) {
+			changes = append(changes, exportedChanges(exported)...)
+		}
+		changes = append(changes, ttlChanges(cmdCtx, envName, nil, nil)...)
+		d.WriteScript(cmdCtx.Stdout, cmdCtx.App.Name, changes)
+		return nil
+	}
+
+	exportables, err := es.EnvExportableList(ctx, envName)
+	if err != nil {
+		if errors.Is(err, models.ErrEnvNotFound) && noEnvNoProblem {
+			return nil
+		}
+		return fmt.Errorf("could not list exportable env vars: %s: %w", envName, err)
+	}
+
+	envAliases := map[string]models.Alias{}
+	if scriptType == "export" {
+		envAliases, err = resolveAliases(ctx, es, []string{envName})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(exportables) == 0 && len(exported.namesInEnv(envName)) == 0 &&
+		len(envAliases) == 0 && len(aliases.namesInEnv(envName)) == 0 {
+		return nil
+	}
+
+	changes := make([]shellChange, 0, len(exportables))
+	switch scriptType {
+	case "export":
+		exportables, failed := resolveExportableValues(ctx, es, cmdCtx, exportables)
+		warnFailedVars(cmdCtx, failed)
+		// forget (and unset below) what this env exported last time. The
+		// enabled vars are recorded again as they're exported.
+		stale := exported.namesInEnv(envName)
+		for _, name := range stale {
+			delete(exported, name)
+		}
+		kvs := map[string]string{}
+		for _, e := range exportables {
+			switch {
+			case !e.Enabled:
+			case len(e.Scope) > 0:
+				// only recorded for the wrappers of its commands
+				exported[e.Name] = exportedVar{Env: envName, Fingerprint: fingerprint(e.Value), Scope: strings.Join(e.Scope, ","), Shadowed: nil}
+			default:
+				changes = append(changes, shellChange{Op: shellChangeOpAdd, Name: e.Name, Value: e.Value, Silent: false})
+				exported[e.Name] = exportedVar{Env: envName, Fingerprint: fingerprint(e.Value), Scope: "", Shadowed: nil}
+				kvs[e.Name] = e.Value
+			}
+		}
+		changes = append(changes, failedChanges(failed)...)
+		for _, name := range stale {
+			if before[name].Scope != "" {
+				continue
+			}
+			// unset it if nothing exports it now, or if it's scoped now
+			if ev, exists := exported[name]; !exists || ev.Scope != "" {
+				changes = append(changes, shellChange{Op: shellChangeOpRemove, Name: name, Value: "", Silent: false})
+			}
+		}
+		changes = append(changes, aliasTransition(aliases, []string{envName}, envAliases)...)
+		if !maps.EqualFunc(before, exported, exportedVar.equal) {
+			changes = append(changes, exportedChanges(exported)...)
+		}
+		changes = append(changes, ttlChanges(cmdCtx, envName, kvs, stale)...)
+	case "unexport":
+		for _, e := range exportables {
+			if len(e.Scope) > 0 {
+				continue
+			}
+			changes = append(changes, shellChange{Op: shellChangeOpRemove, Name: e.Name, Value: e.Value, Silent: false})
+		}
+		changes = append(changes, aliasTransition(aliases, []string{envName}, nil)...)
+	default:
+		return errors.New("unimplemented --script-type: " + scriptType)
+	}
+	d.WriteScript(cmdCtx.Stdout, cmdCtx.App.Name, changes)
+	return nil
+}
+
+func shellChdirCmd(d shellDialect) warg.Cmd {
+	return warg.NewCmd(
+		"Change directory and corresponding env vars",
+		withSetup(func(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+			return shellChdirRun(ctx, es, cmdCtx, d)
+		}),
+		// TODO: maybe define the flags here to get better descriptions.
+		warg.CmdFlag("--old", envNameFlag()),
+		warg.CmdFlag("--new", envNameFlag()),
+		warg.CmdFlagMap(hierarchicalFlagMap()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+		warg.CmdFlagMap(shellNowFlagMap()),
+	)
+}
+
+type LookupEnvFunc = func(key string) (string, bool)
+type CustomLookupEnvFuncKey struct{}
+
+// lookupEnvFromCtx returns the CustomLookupEnvFuncKey func if passed in the
+// parse metadata, and os.LookupEnv otherwise
+func lookupEnvFromCtx(cmdCtx warg.CmdContext) LookupEnvFunc {
+	if custom, exists := cmdCtx.ParseMetadata.Get(CustomLookupEnvFuncKey{}); exists {
+		return custom.(LookupEnvFunc)
+	}
+	return os.LookupEnv
+}
+
+// LookupMap loooks up keys from a provided map. Useful to mock os.LookupEnv when parsing
+func LookupMap(m map[string]string) LookupEnvFunc {
+	return func(key string) (string, bool) {
+		val, exists := m[key]
+		return val, exists
+	}
+}
+
+func shellChdirRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext, d shellDialect) error {
+	pc := newPathCanonicalizer(cmdCtx)
+	oldDir := pc.Dir(cmdCtx.Flags["--old"].(string))
+	newDir := pc.Dir(cmdCtx.Flags["--new"].(string))
+	hierarchical := cmdCtx.Flags["--hierarchical"].(bool)
+
+	lookupEnv := lookupEnvFromCtx(cmdCtx)
+
+	oldEnvNames, err := activeDirEnvNames(ctx, es, pc, lookupEnv, oldDir, hierarchical)
+	if err != nil {
+		return err
+	}
+	newEnvNames, err := dirEnvNames(ctx, es, pc, newDir, hierarchical)
+	if err != nil {
+		return err
+	}
+
+	dirEnvsChange, err := dirEnvsChanges(ctx, es, lookupEnv, newEnvNames)
+	if err != nil {
+		return err
+	}
+
+	// pushed envs stay on top of the directory envs
+	stack := readStack(lookupEnv)
+	return writeEnvTransition(
+		ctx, es, cmdCtx, d,
+		slices.Concat(oldEnvNames, stack),
+		slices.Concat(newEnvNames, stack),
+		dirEnvsChange,
+	)
+}
+
+// dirEnvsChanges returns a silent change saving the envs in envNames that
+// exist to shellDirEnvsVar, if they differ from the recorded ones. An empty
+// list is saved too, so the shell stays recorded.
+func dirEnvsChanges(ctx context.Context, es models.Service, lookupEnv LookupEnvFunc, envNames []string) ([]shellChange, error) {
+	existing := []string{}
+	for _, envName := range envNames {
+		_, err := es.EnvShow(ctx, envName)
+		if errors.Is(err, models.ErrEnvNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not show env: %s: %w", envName, err)
+		}
+		existing = append(existing, envName)
+	}
+	recordedNames, recorded := readDirEnvs(lookupEnv)
+	if recorded && slices.Equal(recordedNames, existing) {
+		return nil, nil
+	}
+	// Marshalling a slice of strings can't fail
+	buf, _ := json.Marshal(existing)
+	return []shellChange{{Op: shellChangeOpChange, Name: shellDirEnvsVar, Value: string(buf), Silent: true}}, nil
+}
+
+// activeDirEnvNames returns the envs activated for the shell's directory: the
+// ones recorded in shellDirEnvsVar, or dir's envs for shells that started
+// before enventory recorded them
+func activeDirEnvNames(ctx context.Context, es models.Service, pc pathCanonicalizer, lookupEnv LookupEnvFunc, dir string, hierarchical bool) ([]string, error) {
+	if envNames, recorded := readDirEnvs(lookupEnv); recorded {
+		return envNames, nil
+	}
+	return dirEnvNames(ctx, es, pc, dir, hierarchical)
+}
+
+// writeEnvTransition writes a script moving the shell from having
+// oldEnvNames exported to having newEnvNames exported, both ordered from
+// lowest to highest precedence. It only changes vars whose values differ, and
+// restores values the old envs shadowed. extra changes are appended to the
+// script, followed by the hooks of the envs being left and entered.
+func writeEnvTransition(ctx context.Context, es models.Service, cmdCtx warg.CmdContext, d shellDialect, oldEnvNames []string, newEnvNames []string, extra []shellChange) error {
+	lookupEnv := lookupEnvFromCtx(cmdCtx)
+
+	newResolved, err := resolveExportables(ctx, es, newEnvNames)
+	if err != nil {
+		return fmt.Errorf("could not resolve new envs: %w", err)
+	}
+	// failed command vars are left out and mentioned in the summary
+	failed := resolveVarValues(ctx, es, cmdCtx, newResolved)
+	warnFailedVars(cmdCtx, failed)
+	newKVs := make(map[string]string, len(newResolved))
+	// scoped vars are only recorded, so the wrappers for their commands can
+	// find them
+	newScoped := make(map[string]resolvedVar)
+	for name, rv := range newResolved {
+		switch {
+		case !rv.Enabled:
+		case len(rv.Scope) > 0:
+			newScoped[name] = rv
+		default:
+			newKVs[name] = rv.Value
+		}
+	}
+
+	oldKVs := make(map[string]string)
+	// managed vars were exported by the old envs, so their current values
+	// aren't the user's own and shouldn't be restored later
+	managed := make(map[string]bool)
+
+	exported, tracked := readExported(lookupEnv)
+	before := maps.Clone(exported)
+	if tracked {
+		// The shell recorded what the old envs exported, so vars deleted or
+		// renamed since then are still unset
+		for _, envName := range oldEnvNames {
+			for _, name := range exported.namesInEnv(envName) {
+				if exported[name].Scope != "" {
+					// never exported into the shell
+					delete(exported, name)
+					continue
+				}
+				current, _ := lookupEnv(name)
+				managed[name] = fingerprint(current) == exported[name].Fingerprint
+				if shadowed := exported[name].Shadowed; shadowed != nil {
+					// the var's shadowed value is restored too. If the new
+					// envs export it, it's shadowed again below
+					exported[name] = *shadowed
+				} else {
+					delete(exported, name)
+				}
+				if _, exists := newKVs[name]; !exists {
+					oldKVs[name] = ""
+				}
+			}
+		}
+	} else {
+		// Fall back to the old envs' current vars for shells that started
+		// before enventory recorded exports
+		oldResolved, err := resolveExportables(ctx, es, oldEnvNames)
+		if err != nil {
+			return fmt.Errorf("could not resolve old envs: %w", err)
+		}
+		for name, rv := range oldResolved {
+			if len(rv.Scope) > 0 {
+				continue
+			}
+			if rv.Enabled {
+				managed[name] = true
+			}
+			// if it exists in the new env, we don't need to process in the old env
+			// Let's also not consider enabled here, as these are slated to be removed anyway. So we want to unset them even if they are disabled in the old env, as long as they don't exist in the new env.
+			if _, exists := newKVs[name]; exists {
+				continue
+			}
+			oldKVs[name] = rv.Value
+		}
+	}
+	for name, value := range newKVs {
+		ev := exportedVar{Env: newResolved[name].Env, Fingerprint: fingerprint(value), Scope: "", Shadowed: nil}
+		// what's left was exported by envs outside this transition, like
+		// ones from export-env
+		if prev, exists := exported[name]; exists && prev.Env != ev.Env {
+			ev.Shadowed = &prev
+		}
+		exported[name] = ev
+	}
+	for name, rv := range newScoped {
+		exported[name] = exportedVar{Env: rv.Env, Fingerprint: fingerprint(rv.Value), Scope: strings.Join(rv.Scope, ","), Shadowed: nil}
+	}
+
+	todo := computeExportChanges(oldKVs, newKVs, lookupEnv)
+	todo, stateChanges := shadowValues(todo, managed, lookupEnv)
+	if !maps.EqualFunc(before, exported, exportedVar.equal) {
+		stateChanges = append(stateChanges, exportedChanges(exported)...)
+	}
+
+	newAliases, err := resolveAliases(ctx, es, newEnvNames)
+	if err != nil {
+		return fmt.Errorf("could not resolve new aliases: %w", err)
+	}
+	aliasChanges := aliasTransition(readAliases(lookupEnv), oldEnvNames, newAliases)
+
+	hooks, err := resolveHooks(ctx, es, newTrustStore(cmdCtx), oldEnvNames, newEnvNames)
+	if err != nil {
+		return fmt.Errorf("could not resolve hooks: %w", err)
+	}
+
+	d.WriteScript(cmdCtx.Stdout, cmdCtx.App.Name, slices.Concat(changesFromResult(todo), failedChanges(failed), aliasChanges, stateChanges, extra))
+	// hooks run after the export statements so they see the new vars
+	d.WriteHooks(cmdCtx.Stdout, cmdCtx.App.Name, hooks)
+	return nil
+}
+
+type kv struct {
+	Name  string
+	Value string
+}
+
+type computeExportChangesResult struct {
+	ToAdd     []kv
+	ToChange  []kv
+	ToRemove  []kv
+	Unchanged []kv
+}
+
+func computeExportChanges(oldKVs, newKVs map[string]string, lookupFunc func(string) (string, bool)) computeExportChangesResult {
+	res := computeExportChangesResult{
+		ToAdd:     nil,
+		ToChange:  nil,
+		ToRemove:  nil,
+		Unchanged: nil,
+	}
+
+	for key, val := range oldKVs {
+		_, exists := lookupFunc(key)
+		if exists {
+			res.ToRemove = append(res.ToRemove, kv{Name: key, Value: val})
+		}
+	}
+
+	for key, val := range newKVs {
+		envVal, exists := lookupFunc(key)
+		if exists {
+			if envVal == val {
+				res.Unchanged = append(res.Unchanged, kv{Name: key, Value: val})
+			} else {
+				res.ToChange = append(res.ToChange, kv{Name: key, Value: val})
+			}
+		} else {
+			res.ToAdd = append(res.ToAdd, kv{Name: key, Value: val})
+		}
+	}
+	cmp := func(a, b kv) int {
+		return strings.Compare(a.Name, b.Name)
+	}
+	slices.SortFunc(res.ToAdd, cmp)
+	slices.SortFunc(res.ToChange, cmp)
+	slices.SortFunc(res.ToRemove, cmp)
+	slices.SortFunc(res.Unchanged, cmp)
+	return res
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"go.bbkane.com/enventory/models"
+)
+
+// Aliases and functions (alias create) are defined by the same scripts that
+// export vars. The shell can't report which aliases it has, so the scripts
+// record the ones enventory defined in shellAliasesVar and only remove those.
+
+// shellAliasesVar holds a JSON object mapping the names of the aliases and
+// functions enventory defined to a definedAlias.
+const shellAliasesVar = "ENVENTORY_ALIASES"
+
+// definedAlias records which env defined an alias or function
+type definedAlias struct {
+	Env         string           `json:"env"`
+	Kind        models.AliasKind `json:"kind"`
+	Fingerprint string           `json:"fp"`
+}
+
+type definedAliases map[string]definedAlias
+
+// namesInEnv returns the sorted names of aliases defined by envName
+func (d definedAliases) namesInEnv(envName string) []string {
+	names := []string{}
+	for name, da := range d {
+		if da.Env == envName {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// readAliases reads shellAliasesVar. A missing or invalid value is treated as
+// empty.
+func readAliases(lookupEnv LookupEnvFunc) definedAliases {
+	aliases := definedAliases{}
+	val, exists := lookupEnv(shellAliasesVar)
+	if !exists || val == "" {
+		return aliases
+	}
+	err := json.Unmarshal([]byte(val), &aliases)
+	if err != nil {
+		return definedAliases{}
+	}
+	return aliases
+}
+
+// resolveAliases merges the enabled aliases of the enabled envs in envNames,
+// ordered from lowest to highest precedence. Envs that don't exist are
+// skipped.
+func resolveAliases(ctx context.Context, es models.Service, envNames []string) (map[string]models.Alias, error) {
+	resolved := map[string]models.Alias{}
+	for _, envName := range envNames {
+		env, err := es.EnvShow(ctx, envName)
+		if errors.Is(err, models.ErrEnvNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not show env: %s: %w", envName, err)
+		}
+		if !env.Enabled {
+			continue
+		}
+		aliases, err := es.AliasList(ctx, envName)
+		if err != nil {
+			return nil, fmt.Errorf("could not list aliases: %s: %w", envName, err)
+		}
+		for _, a := range aliases {
+			if a.Enabled {
+				resolved[a.Name] = a
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// aliasTransition returns the changes removing the aliases defined by
+// dropEnvs and defining add, followed by a silent change recording the result
+// if it differs from defined. Aliases that are already defined with the same
+// value are only mentioned.
+func aliasTransition(defined definedAliases, dropEnvs []string, add map[string]models.Alias) []shellChange {
+	after := maps.Clone(defined)
+	for name, da := range defined {
+		if slices.Contains(dropEnvs, da.Env) {
+			delete(after, name)
+		}
+	}
+	for name, a := range add {
+		after[name] = definedAlias{Env: a.EnvName, Kind: a.Kind, Fingerprint: fingerprint(a.Value)}
+	}
+
+	changes := []shellChange{}
+	for _, name := range slices.Sorted(maps.Keys(add)) {
+		a := add[name]
+		prev, exists := defined[name]
+		switch {
+		case !exists:
+			changes = append(changes, shellChange{Op: shellChangeOpAdd, Name: name, Value: a.Value, Alias: a.Kind})
+		case prev.Kind != a.Kind:
+			// a function and an alias with the same name would both apply
+			changes = append(changes,
+				shellChange{Op: shellChangeOpRemove, Name: name, Value: "", Silent: true, Alias: prev.Kind},
+				shellChange{Op: shellChangeOpChange, Name: name, Value: a.Value, Alias: a.Kind},
+			)
+		case prev.Fingerprint != fingerprint(a.Value):
+			changes = append(changes, shellChange{Op: shellChangeOpChange, Name: name, Value: a.Value, Alias: a.Kind})
+		default:
+			changes = append(changes, shellChange{Op: shellChangeOpUnchanged, Name: name, Value: a.Value, Alias: a.Kind})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(defined)) {
+		if _, exists := after[name]; !exists {
+			changes = append(changes, shellChange{Op: shellChangeOpRemove, Name: name, Value: "", Alias: defined[name].Kind})
+		}
+	}
+	if !maps.Equal(defined, after) {
+		changes = append(changes, stateChange(shellAliasesVar, after))
+	}
+	return changes
+}
+
+// functionBody trims what would end a function body early, so it can be
+// followed by "; }" or "; end"
+func functionBody(value string) string {
+	return strings.TrimRight(value, "; \t\n")
+}
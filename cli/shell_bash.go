@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+
+	"al.essio.dev/pkg/shellescape"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/path"
+)
+
+func ShellBashInitCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Prints the bash initialization script",
+		shellBashInitRun,
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func shellBashInitRun(cmdCtx warg.CmdContext) error {
+	dbPathArg := "--db-path " + shellescape.Quote(cmdCtx.Flags["--db-path"].(path.Path).MustExpand())
+
+	prelude := `
+# https://github.com/bbkane/enventory/
+#
+# To initialize enventory, add this to your configuration (usually ~/.bashrc):
+#
+# eval "$(enventory shell bash init)"
+#
+`
+	fmt.Fprint(cmdCtx.Stdout, prelude)
+
+	// bash doesn't have a chpwd hook, so check whether $PWD changed before
+	// each prompt instead. Like zsh's chpwd, this doesn't fire when the
+	// shell starts.
+	chpwdHook := `
+__enventory_oldpwd="$PWD"
+__enventory_chpwd() {
+    if [[ "$__enventory_oldpwd" != "$PWD" ]]; then
+        eval "$(enventory shell bash chdir ` + dbPathArg + ` --old "$__enventory_oldpwd" --new "$PWD")"
+        __enventory_oldpwd="$PWD"
+    fi
+}
+if [[ ";${PROMPT_COMMAND:-};" != *";__enventory_chpwd;"* ]]; then
+    PROMPT_COMMAND="__enventory_chpwd${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+`
+	fmt.Fprint(cmdCtx.Stdout, chpwdHook)
+
+	exportEnv := `
+export-env() { eval "$(enventory shell bash export ` + dbPathArg + ` --env "$1" --no-env-no-problem true)"; }
+unexport-env() { eval "$(enventory shell bash unexport ` + dbPathArg + ` --env "$1" --no-env-no-problem true)"; }
+push-env() { eval "$(enventory shell bash push ` + dbPathArg + ` --env "$1")"; }
+pop-env() { eval "$(enventory shell bash pop ` + dbPathArg + `)"; }
+`
+	fmt.Fprint(cmdCtx.Stdout, exportEnv)
+
+	return nil
+}
+
+func ShellBashExportCmd() warg.Cmd {
+	return shellExportCmd(posixDialect())
+}
+
+func ShellBashUnexportCmd() warg.Cmd {
+	return shellUnexportCmd(posixDialect())
+}
+
+func ShellBashChdirCmd() warg.Cmd {
+	return shellChdirCmd(posixDialect())
+}
+
+func ShellBashPushCmd() warg.Cmd {
+	return shellPushCmd(posixDialect())
+}
+
+func ShellBashPopCmd() warg.Cmd {
+	return shellPopCmd(posixDialect())
+}
+
+func ShellBashStackCmd() warg.Cmd {
+	return shellStackCmd()
+}
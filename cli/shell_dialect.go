@@ -0,0 +1,349 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"al.essio.dev/pkg/shellescape"
+	"go.bbkane.com/enventory/models"
+)
+
+// shellChangeOp is the symbol printed in the summary line for a change.
+type shellChangeOp string
+
+const (
+	shellChangeOpAdd       shellChangeOp = "+"
+	shellChangeOpChange    shellChangeOp = "~"
+	shellChangeOpRemove    shellChangeOp = "-"
+	shellChangeOpUnchanged shellChangeOp = "="
+	// shellChangeOpFailed marks a command var whose command failed, so it
+	// wasn't exported
+	shellChangeOpFailed shellChangeOp = "!"
+)
+
+// shellChange is one var (or alias) to export (Add, Change), unset (Remove),
+// or just mention in the summary (Unchanged, Failed)
+type shellChange struct {
+	Op    shellChangeOp
+	Name  string
+	Value string
+	// Silent changes are applied but left out of the summary. enventory uses
+	// them for its own state vars.
+	Silent bool
+	// Alias is the kind of alias or function to define (Add, Change) or
+	// remove (Remove). It's empty for vars.
+	Alias models.AliasKind
+}
+
+// summaryName is how a change's name appears in the summary. Aliases and
+// functions get a () suffix to tell them from vars.
+func (c shellChange) summaryName(name string) string {
+	if c.Alias != "" {
+		return name + "()"
+	}
+	return name
+}
+
+// hasVisible reports whether any change should appear in the summary
+func hasVisible(changes []shellChange) bool {
+	for _, c := range changes {
+		if !c.Silent {
+			return true
+		}
+	}
+	return false
+}
+
+// changesFromResult flattens a computeExportChangesResult into changes in the
+// order they should be printed: Added, Changed, Removed, Unchanged
+func changesFromResult(res computeExportChangesResult) []shellChange {
+	changes := make([]shellChange, 0, len(res.ToAdd)+len(res.ToChange)+len(res.ToRemove)+len(res.Unchanged))
+	for _, kv := range res.ToAdd {
+		changes = append(changes, shellChange{Op: shellChangeOpAdd, Name: kv.Name, Value: kv.Value})
+	}
+	for _, kv := range res.ToChange {
+		changes = append(changes, shellChange{Op: shellChangeOpChange, Name: kv.Name, Value: kv.Value})
+	}
+	for _, kv := range res.ToRemove {
+		changes = append(changes, shellChange{Op: shellChangeOpRemove, Name: kv.Name, Value: kv.Value})
+	}
+	for _, kv := range res.Unchanged {
+		changes = append(changes, shellChange{Op: shellChangeOpUnchanged, Name: kv.Name, Value: kv.Value})
+	}
+	return changes
+}
+
+// failedChanges mentions the command vars whose commands failed
+func failedChanges(failed map[string]error) []shellChange {
+	changes := make([]shellChange, 0, len(failed))
+	for _, name := range slices.Sorted(maps.Keys(failed)) {
+		changes = append(changes, shellChange{Op: shellChangeOpFailed, Name: name, Value: ""})
+	}
+	return changes
+}
+
+// shellDialect writes the script the shell evaluates to apply changes.
+type shellDialect interface {
+	// WriteScript writes a script applying changes and printing a summary line
+	// prefixed by appName. It writes nothing if there are no changes, and
+	// omits the summary if all changes are silent.
+	WriteScript(w io.Writer, appName string, changes []shellChange)
+	// WriteHooks writes statements running the trusted hooks in order and
+	// warning about the untrusted ones
+	WriteHooks(w io.Writer, appName string, hooks []shellHook)
+}
+
+// lineDialect is a shellDialect for shells that can eval a script with one
+// statement per line.
+type lineDialect struct {
+	// quoteName escapes a var name for exportFmt, unsetFmt, and the summary
+	quoteName func(string) string
+	// quoteValue escapes a var value for exportFmt
+	quoteValue func(string) string
+	// printFmt formats summary text into a statement printing it without a newline
+	printFmt string
+	// printlnStmt ends the summary line
+	printlnStmt string
+	// exportFmt formats the quoted name and value into an export statement
+	exportFmt string
+	// unsetFmt formats the quoted name into an unset statement
+	unsetFmt string
+	// aliasStmt returns a statement defining an alias or function
+	aliasStmt func(kind models.AliasKind, name string, value string) string
+	// unaliasStmt returns a statement removing an alias or function
+	unaliasStmt func(kind models.AliasKind, name string) string
+	// runFmt formats a quoted command into a statement running it in the
+	// current shell
+	runFmt string
+	// warnFmt formats a quoted message into a statement printing it to stderr
+	warnFmt string
+}
+
+func (d lineDialect) WriteScript(w io.Writer, appName string, changes []shellChange) {
+	if len(changes) == 0 {
+		return
+	}
+	visible := hasVisible(changes)
+	if visible {
+		fmt.Fprintf(w, d.printFmt+"\n", appName+":")
+	}
+	for _, c := range changes {
+		if !c.Silent {
+			fmt.Fprintf(w, d.printFmt+"\n", " "+string(c.Op)+c.summaryName(d.quoteName(c.Name)))
+		}
+		switch {
+		case c.Op == shellChangeOpUnchanged, c.Op == shellChangeOpFailed:
+			// only mentioned in the summary
+		case c.Alias != "" && c.Op == shellChangeOpRemove:
+			fmt.Fprintln(w, d.unaliasStmt(c.Alias, c.Name))
+		case c.Alias != "":
+			fmt.Fprintln(w, d.aliasStmt(c.Alias, c.Name, c.Value))
+		case c.Op == shellChangeOpRemove:
+			fmt.Fprintf(w, d.unsetFmt+"\n", d.quoteName(c.Name))
+		default:
+			fmt.Fprintf(w, d.exportFmt+"\n", d.quoteName(c.Name), d.quoteValue(c.Value))
+		}
+	}
+	if visible {
+		fmt.Fprint(w, d.printlnStmt+"\n")
+	}
+}
+
+func (d lineDialect) WriteHooks(w io.Writer, appName string, hooks []shellHook) {
+	for _, h := range hooks {
+		if h.Trusted {
+			fmt.Fprintf(w, d.runFmt+"\n", d.quoteValue(h.Command))
+		} else {
+			fmt.Fprintf(w, d.warnFmt+"\n", d.quoteValue(untrustedHookMessage(appName, h)))
+		}
+	}
+}
+
+// posixDialect works for zsh and bash
+func posixDialect() shellDialect {
+	return lineDialect{
+		quoteName:   shellescape.Quote,
+		quoteValue:  shellescape.Quote,
+		printFmt:    "printf '%s';",
+		printlnStmt: "echo;",
+		exportFmt:   "export %s=%s;",
+		unsetFmt:    "unset %s;",
+		aliasStmt:   posixAliasStmt,
+		unaliasStmt: posixUnaliasStmt,
+		runFmt:      "eval %s;",
+		warnFmt:     "echo %s >&2;",
+	}
+}
+
+// posixAliasStmt defines functions with the function keyword since a name
+// after it isn't alias expanded. The definition goes through eval so it stays
+// one statement even though generated scripts are usually run with an
+// unquoted $(...).
+func posixAliasStmt(kind models.AliasKind, name string, value string) string {
+	if kind == models.AliasKindFunction {
+		// an alias with the same name would be expanded instead of calling it
+		def := "function " + name + " { " + functionBody(value) + "; }"
+		return "unalias " + name + " 2>/dev/null; eval " + shellescape.Quote(def) + ";"
+	}
+	return "alias " + name + "=" + shellescape.Quote(value) + ";"
+}
+
+func posixUnaliasStmt(kind models.AliasKind, name string) string {
+	if kind == models.AliasKindFunction {
+		return "unset -f " + name + ";"
+	}
+	return "unalias " + name + " 2>/dev/null;"
+}
+
+func fishDialect() shellDialect {
+	return lineDialect{
+		quoteName:   fishQuote,
+		quoteValue:  fishQuote,
+		printFmt:    "printf '%s';",
+		printlnStmt: "echo;",
+		exportFmt:   "set -gx %s %s;",
+		unsetFmt:    "set -e %s;",
+		aliasStmt:   fishAliasStmt,
+		unaliasStmt: fishUnaliasStmt,
+		runFmt:      "eval %s;",
+		warnFmt:     "echo %s >&2;",
+	}
+}
+
+// fishAliasStmt defines both kinds as functions, since that's what fish's
+// alias does
+func fishAliasStmt(kind models.AliasKind, name string, value string) string {
+	if kind == models.AliasKindFunction {
+		return "function " + name + "; " + functionBody(value) + "; end;"
+	}
+	return "alias " + name + " " + fishQuote(value) + ";"
+}
+
+func fishUnaliasStmt(_ models.AliasKind, name string) string {
+	return "functions -e " + name + ";"
+}
+
+func pwshDialect() shellDialect {
+	return lineDialect{
+		quoteName:   pwshBracedVarName,
+		quoteValue:  pwshQuote,
+		printFmt:    "Write-Host -NoNewline '%s';",
+		printlnStmt: "Write-Host;",
+		exportFmt:   "${env:%s} = %s;",
+		// assigning $null to an env var removes it
+		unsetFmt:    "${env:%s} = $null;",
+		aliasStmt:   pwshAliasStmt,
+		unaliasStmt: pwshUnaliasStmt,
+		runFmt:      "Invoke-Expression %s;",
+		warnFmt:     "Write-Warning %s;",
+	}
+}
+
+// pwshAliasStmt defines both kinds as global functions, since PowerShell
+// aliases can't include arguments. An alias passes its arguments through.
+func pwshAliasStmt(kind models.AliasKind, name string, value string) string {
+	if kind == models.AliasKindFunction {
+		return "function global:" + name + " { " + value + " };"
+	}
+	return "function global:" + name + " { " + value + " @args };"
+}
+
+func pwshUnaliasStmt(_ models.AliasKind, name string) string {
+	return "Remove-Item -Path Function:\\" + name + " -ErrorAction SilentlyContinue;"
+}
+
+//nolint:gochecknoglobals // compiled once
+var fishSafeWord = regexp.MustCompile(`^[a-zA-Z0-9_@%+=:,./-]+$`)
+
+// fishQuote quotes a string for fish. Inside fish's single quotes, only
+// backslashes and single quotes need escaping.
+func fishQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if fishSafeWord.MatchString(s) {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
+}
+
+// pwshQuote quotes a string for PowerShell. Inside PowerShell's single quotes,
+// only single quotes need escaping (by doubling them).
+func pwshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// nuQuote quotes a string for nushell. Single-quoted strings have no
+// escapes, so strings containing a single quote use a raw string with enough
+// #s that the string can't end it.
+func nuQuote(s string) string {
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'"
+	}
+	hashes := "#"
+	for strings.Contains(s, "'"+hashes) {
+		hashes += "#"
+	}
+	return "r" + hashes + "'" + s + "'" + hashes
+}
+
+// pwshBracedVarName escapes a var name to go inside ${env:...}
+func pwshBracedVarName(s string) string {
+	return strings.NewReplacer("`", "``", "{", "`{", "}", "`}").Replace(s)
+}
+
+// nuDialect writes a JSON document instead of a script because nushell can't
+// eval strings. The init script defines a command to apply it.
+type nuDialect struct{}
+
+type nuScript struct {
+	// Summary is empty if all changes are silent
+	Summary string            `json:"summary"`
+	Set     map[string]string `json:"set"`
+	Unset   []string          `json:"unset"`
+}
+
+func (nuDialect) WriteScript(w io.Writer, appName string, changes []shellChange) {
+	// nushell can't define aliases or functions at runtime, so don't define
+	// or record them
+	changes = slices.DeleteFunc(slices.Clone(changes), func(c shellChange) bool {
+		return c.Alias != "" || c.Name == shellAliasesVar
+	})
+	if len(changes) == 0 {
+		return
+	}
+	script := nuScript{
+		Summary: "",
+		Set:     map[string]string{},
+		Unset:   []string{},
+	}
+	if hasVisible(changes) {
+		script.Summary = appName + ":"
+	}
+	for _, c := range changes {
+		if !c.Silent {
+			script.Summary += " " + string(c.Op) + c.Name
+		}
+		switch c.Op {
+		case shellChangeOpAdd, shellChangeOpChange:
+			script.Set[c.Name] = c.Value
+		case shellChangeOpRemove:
+			script.Unset = append(script.Unset, c.Name)
+		case shellChangeOpUnchanged, shellChangeOpFailed:
+			// only mentioned in the summary
+		}
+	}
+	// Marshalling a struct of strings can't fail
+	buf, _ := json.Marshal(script)
+	fmt.Fprintf(w, "%s\n", buf)
+}
+
+// WriteHooks writes nothing, since nushell can't eval the hooks' commands
+func (nuDialect) WriteHooks(io.Writer, string, []shellHook) {}
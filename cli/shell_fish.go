@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/path"
+)
+
+func ShellFishInitCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Prints the fish initialization script",
+		shellFishInitRun,
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func shellFishInitRun(cmdCtx warg.CmdContext) error {
+	dbPathArg := "--db-path " + fishQuote(cmdCtx.Flags["--db-path"].(path.Path).MustExpand())
+
+	prelude := `
+# https://github.com/bbkane/enventory/
+#
+# To initialize enventory, add this to your configuration (usually ~/.config/fish/config.fish):
+#
+# enventory shell fish init | source
+#
+`
+	fmt.Fprint(cmdCtx.Stdout, prelude)
+
+	// fish only keeps the previous directory in $dirprev, which isn't updated by
+	// every directory change, so track it ourselves.
+	chpwdHook := `
+set -g __enventory_oldpwd $PWD
+function __enventory_chpwd --on-variable PWD
+    enventory shell fish chdir ` + dbPathArg + ` --old "$__enventory_oldpwd" --new "$PWD" | source
+    set -g __enventory_oldpwd $PWD
+end
+`
+	fmt.Fprint(cmdCtx.Stdout, chpwdHook)
+
+	exportEnv := `
+function export-env
+    enventory shell fish export ` + dbPathArg + ` --env "$argv[1]" --no-env-no-problem true | source
+end
+function unexport-env
+    enventory shell fish unexport ` + dbPathArg + ` --env "$argv[1]" --no-env-no-problem true | source
+end
+function push-env
+    enventory shell fish push ` + dbPathArg + ` --env "$argv[1]" | source
+end
+function pop-env
+    enventory shell fish pop ` + dbPathArg + ` | source
+end
+`
+	fmt.Fprint(cmdCtx.Stdout, exportEnv)
+
+	return nil
+}
+
+func ShellFishExportCmd() warg.Cmd {
+	return shellExportCmd(fishDialect())
+}
+
+func ShellFishUnexportCmd() warg.Cmd {
+	return shellUnexportCmd(fishDialect())
+}
+
+func ShellFishChdirCmd() warg.Cmd {
+	return shellChdirCmd(fishDialect())
+}
+
+func ShellFishPushCmd() warg.Cmd {
+	return shellPushCmd(fishDialect())
+}
+
+func ShellFishPopCmd() warg.Cmd {
+	return shellPopCmd(fishDialect())
+}
+
+func ShellFishStackCmd() warg.Cmd {
+	return shellStackCmd()
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.bbkane.com/enventory/models"
+)
+
+// hookEvent is when a hook runs
+type hookEvent string
+
+const (
+	hookEventEnter hookEvent = "on-enter"
+	hookEventLeave hookEvent = "on-leave"
+)
+
+// shellHook is a command to run in the shell after its vars are exported.
+// Untrusted hooks are reported instead of run.
+type shellHook struct {
+	EnvName string
+	Event   hookEvent
+	Command string
+	Trusted bool
+}
+
+// resolveHooks returns the hooks to run when the shell moves from oldEnvNames
+// to newEnvNames, both ordered from lowest to highest precedence. The leaving
+// envs' on-leave hooks run first, from highest to lowest precedence, followed
+// by the entering envs' on-enter hooks, from lowest to highest. Envs that
+// don't exist or are disabled are skipped.
+func resolveHooks(ctx context.Context, es models.Service, ts trustStore, oldEnvNames []string, newEnvNames []string) ([]shellHook, error) {
+	hooks := []shellHook{}
+	seen := map[string]bool{}
+	addHook := func(envName string, event hookEvent) error {
+		if seen[envName] {
+			return nil
+		}
+		seen[envName] = true
+		env, err := es.EnvShow(ctx, envName)
+		if errors.Is(err, models.ErrEnvNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not show env: %s: %w", envName, err)
+		}
+		if !env.Enabled {
+			return nil
+		}
+		command := env.OnEnter
+		if event == hookEventLeave {
+			command = env.OnLeave
+		}
+		if command == "" {
+			return nil
+		}
+		trusted, err := envTrusted(ctx, es, ts, *env)
+		if err != nil {
+			return err
+		}
+		hooks = append(hooks, shellHook{
+			EnvName: envName,
+			Event:   event,
+			Command: command,
+			Trusted: trusted != nil && *trusted,
+		})
+		return nil
+	}
+
+	for _, envName := range slices.Backward(oldEnvNames) {
+		if !slices.Contains(newEnvNames, envName) {
+			if err := addHook(envName, hookEventLeave); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, envName := range newEnvNames {
+		if !slices.Contains(oldEnvNames, envName) {
+			if err := addHook(envName, hookEventEnter); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return hooks, nil
+}
+
+// untrustedHookMessage explains why a hook didn't run and how to run it
+func untrustedHookMessage(appName string, h shellHook) string {
+	return fmt.Sprintf(
+		"%s: skipped untrusted %s hook for %s. Review it, then run: %s env trust --name %s",
+		appName, h.Event, h.EnvName, appName, h.EnvName,
+	)
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/path"
+)
+
+func ShellNuInitCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Prints the nushell initialization script",
+		shellNuInitRun,
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func shellNuInitRun(cmdCtx warg.CmdContext) error {
+	dbPathArg := "--db-path " + nuQuote(cmdCtx.Flags["--db-path"].(path.Path).MustExpand())
+
+	prelude := `
+# https://github.com/bbkane/enventory/
+#
+# To initialize enventory, save this script and source it from your
+# configuration (usually config.nu):
+#
+# enventory shell nu init | save --force ~/.config/nushell/enventory.nu
+# source ~/.config/nushell/enventory.nu
+#
+`
+	fmt.Fprint(cmdCtx.Stdout, prelude)
+
+	// nushell can't eval the output of a command, so the other enventory shell
+	// nu commands print JSON and __enventory_apply loads it into the environment.
+	apply := `
+def --env __enventory_apply [] {
+    let script = $in
+    if ($script | is-empty) { return }
+    let changes = ($script | from json)
+    for name in $changes.unset { hide-env --ignore-errors $name }
+    load-env $changes.set
+    if ($changes.summary | is-not-empty) { print $changes.summary }
+}
+`
+	fmt.Fprint(cmdCtx.Stdout, apply)
+
+	// $before is null when the shell starts. Like zsh's chpwd, don't do
+	// anything then.
+	chpwdHook := `
+$env.config = ($env.config | upsert hooks.env_change.PWD {|config|
+    let hook = {|before, after|
+        if $before != null {
+            enventory shell nu chdir ` + dbPathArg + ` --old $before --new $after | __enventory_apply
+        }
+    }
+    let existing = ($config.hooks?.env_change?.PWD? | default [])
+    $existing | append $hook
+})
+`
+	fmt.Fprint(cmdCtx.Stdout, chpwdHook)
+
+	// export-env is a nushell keyword, so these get different names than the
+	// other shells
+	exportEnv := `
+def --env enventory-export-env [name: string] {
+    enventory shell nu export ` + dbPathArg + ` --env $name --no-env-no-problem true | __enventory_apply
+}
+def --env enventory-unexport-env [name: string] {
+    enventory shell nu unexport ` + dbPathArg + ` --env $name --no-env-no-problem true | __enventory_apply
+}
+def --env enventory-push-env [name: string] {
+    enventory shell nu push ` + dbPathArg + ` --env $name | __enventory_apply
+}
+def --env enventory-pop-env [] {
+    enventory shell nu pop ` + dbPathArg + ` | __enventory_apply
+}
+`
+	fmt.Fprint(cmdCtx.Stdout, exportEnv)
+
+	return nil
+}
+
+func ShellNuExportCmd() warg.Cmd {
+	return shellExportCmd(nuDialect{})
+}
+
+func ShellNuUnexportCmd() warg.Cmd {
+	return shellUnexportCmd(nuDialect{})
+}
+
+func ShellNuChdirCmd() warg.Cmd {
+	return shellChdirCmd(nuDialect{})
+}
+
+func ShellNuPushCmd() warg.Cmd {
+	return shellPushCmd(nuDialect{})
+}
+
+func ShellNuPopCmd() warg.Cmd {
+	return shellPopCmd(nuDialect{})
+}
+
+func ShellNuStackCmd() warg.Cmd {
+	return shellStackCmd()
+}
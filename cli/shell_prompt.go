@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+
+	"go.bbkane.com/warg/value/scalar"
+)
+
+const shellPromptCmdHelpLong = `Print the active env names for a prompt, followed by drift markers:
+
+*  a var changed in the shell since it was exported
+!  the database changed since the vars were exported (only with --check-db)
+
+Nothing is printed when no env is active. Without --check-db, this only reads
+the environment, so it doesn't open the database.
+
+For prompts that can't run commands, ` + shellActiveVar + ` holds the active env
+names (without drift markers).
+
+Examples:
+
+# zsh
+setopt PROMPT_SUBST
+RPROMPT='$(enventory shell prompt --format zsh)'
+
+# starship.toml
+[custom.enventory]
+command = "enventory shell prompt"
+when = "test -n \"$ENVENTORY_ACTIVE$ENVENTORY_SPAWNED\""
+
+# powerlevel10k (in ~/.p10k.zsh, then add enventory to a prompt elements list)
+function prompt_enventory() { p10k segment -t "$(enventory shell prompt)" }`
+
+func ShellPromptCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Print active envs and drift markers for a prompt",
+		shellPromptAction,
+		warg.CmdHelpLong(shellPromptCmdHelpLong),
+		warg.NewCmdFlag(
+			"--format",
+			"plain for starship, powerlevel10k, and other prompt tools. zsh adds colors for PROMPT/RPROMPT",
+			scalar.String(
+				scalar.Choices("plain", "zsh"),
+				scalar.Default("plain"),
+			),
+			warg.Required(),
+		),
+		warg.NewCmdFlag(
+			"--check-db",
+			"Also compare exported vars to the database. Slower since it opens the database",
+			scalar.Bool(
+				scalar.Default(false),
+			),
+			warg.Required(),
+		),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+// shellPromptAction only sets up the database when --check-db needs it
+func shellPromptAction(cmdCtx warg.CmdContext) error {
+	if cmdCtx.Flags["--check-db"].(bool) {
+		return withSetup(func(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+			return shellPromptRun(ctx, es, cmdCtx)
+		})(cmdCtx)
+	}
+	return shellPromptRun(context.Background(), nil, cmdCtx)
+}
+
+func shellPromptRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	format := cmdCtx.Flags["--format"].(string)
+	lookupEnv := lookupEnvFromCtx(cmdCtx)
+
+	exported, _ := readExported(lookupEnv)
+	envNames := []string{}
+	if spawned, exists := lookupEnv(shellSpawnedVar); exists && spawned != "" {
+		envNames = strings.Split(spawned, ",")
+	}
+	for _, name := range exported.envNames() {
+		if !slices.Contains(envNames, name) {
+			envNames = append(envNames, name)
+		}
+	}
+	if len(envNames) == 0 {
+		return nil
+	}
+
+	shellDrift := false
+	for name, ev := range exported {
+		if ev.Scope != "" {
+			continue
+		}
+		current, exists := lookupEnv(name)
+		if !exists || fingerprint(current) != ev.Fingerprint {
+			shellDrift = true
+			break
+		}
+	}
+
+	dbDrift := false
+	if es != nil {
+		var err error
+		dbDrift, err = exportedDBDrift(ctx, es, exported, lookupEnv)
+		if err != nil {
+			return err
+		}
+	}
+
+	markers := ""
+	if shellDrift {
+		markers += "*"
+	}
+	if dbDrift {
+		markers += "!"
+	}
+	segment := strings.Join(envNames, ",") + markers
+
+	switch format {
+	case "zsh":
+		color := "green"
+		if markers != "" {
+			color = "yellow"
+		}
+		fmt.Fprintf(cmdCtx.Stdout, "%%F{%s}%s%%f\n", color, strings.ReplaceAll(segment, "%", "%%"))
+	default:
+		fmt.Fprintln(cmdCtx.Stdout, segment)
+	}
+	return nil
+}
+
+// exportedDBDrift reports whether the envs in exported would export something
+// different now: a var's value, scope, or enabled state changed, a var was
+// deleted or added, or an env was deleted
+func exportedDBDrift(ctx context.Context, es models.Service, exported exportedVars, lookupEnv LookupEnvFunc) (bool, error) {
+	for _, envName := range exported.envNames() {
+		env, err := es.EnvShow(ctx, envName)
+		if errors.Is(err, models.ErrEnvNotFound) {
+			return true, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("could not show env: %s: %w", envName, err)
+		}
+		exportables, err := es.EnvExportableList(ctx, envName)
+		if err != nil {
+			return false, fmt.Errorf("could not list exportable env vars: %s: %w", envName, err)
+		}
+		current := map[string]models.EnvExportable{}
+		all := map[string]models.EnvExportable{}
+		values := map[string]string{}
+		failed := map[string]error{}
+		for _, e := range exportables {
+			if env.Enabled && e.Enabled {
+				current[e.Name] = e
+			}
+			all[e.Name] = e
+			values[e.Name] = e.Value
+			if e.Kind == models.VarKindCommand {
+				// compare to the command's last output instead of running it
+				cache, cached, err := cachedCommandOutput(ctx, es, e)
+				if err != nil {
+					return false, err
+				}
+				if !cached {
+					delete(values, e.Name)
+					failed[e.Name] = models.ErrVarCacheNotFound
+					continue
+				}
+				values[e.Name] = cache.Value
+			}
+		}
+		expandTemplates(values, func(name string) bool { return all[name].IsTemplate() }, failed, lookupEnv)
+		for _, name := range exported.namesInEnv(envName) {
+			e, exists := current[name]
+			if !exists {
+				return true, nil
+			}
+			value, resolved := values[name]
+			if !resolved {
+				return true, nil
+			}
+			if fingerprint(value) != exported[name].Fingerprint || strings.Join(e.Scope, ",") != exported[name].Scope {
+				return true, nil
+			}
+		}
+		for name := range current {
+			// vars recorded for another env override this one's
+			if _, recorded := exported[name]; !recorded {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
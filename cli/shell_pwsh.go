@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/path"
+)
+
+func ShellPwshInitCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Prints the PowerShell initialization script",
+		shellPwshInitRun,
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func shellPwshInitRun(cmdCtx warg.CmdContext) error {
+	dbPathArg := "--db-path " + pwshQuote(cmdCtx.Flags["--db-path"].(path.Path).MustExpand())
+
+	prelude := `
+# https://github.com/bbkane/enventory/
+#
+# To initialize enventory, add this to your configuration (usually $PROFILE):
+#
+# Invoke-Expression (& enventory shell pwsh init | Out-String)
+#
+`
+	fmt.Fprint(cmdCtx.Stdout, prelude)
+
+	// PowerShell doesn't have a directory change hook, so wrap the prompt
+	// function and check whether $PWD changed before each prompt.
+	chpwdHook := `
+$global:__enventory_oldpwd = $PWD.Path
+$global:__enventory_prompt = $function:prompt
+function global:prompt {
+    if ($global:__enventory_oldpwd -ne $PWD.Path) {
+        enventory shell pwsh chdir ` + dbPathArg + ` --old $global:__enventory_oldpwd --new $PWD.Path | Out-String | Invoke-Expression
+        $global:__enventory_oldpwd = $PWD.Path
+    }
+    & $global:__enventory_prompt
+}
+`
+	fmt.Fprint(cmdCtx.Stdout, chpwdHook)
+
+	exportEnv := `
+function global:export-env([string]$name) { enventory shell pwsh export ` + dbPathArg + ` --env $name --no-env-no-problem true | Out-String | Invoke-Expression }
+function global:unexport-env([string]$name) { enventory shell pwsh unexport ` + dbPathArg + ` --env $name --no-env-no-problem true | Out-String | Invoke-Expression }
+function global:push-env([string]$name) { enventory shell pwsh push ` + dbPathArg + ` --env $name | Out-String | Invoke-Expression }
+function global:pop-env { enventory shell pwsh pop ` + dbPathArg + ` | Out-String | Invoke-Expression }
+`
+	fmt.Fprint(cmdCtx.Stdout, exportEnv)
+
+	return nil
+}
+
+func ShellPwshExportCmd() warg.Cmd {
+	return shellExportCmd(pwshDialect())
+}
+
+func ShellPwshUnexportCmd() warg.Cmd {
+	return shellUnexportCmd(pwshDialect())
+}
+
+func ShellPwshChdirCmd() warg.Cmd {
+	return shellChdirCmd(pwshDialect())
+}
+
+func ShellPwshPushCmd() warg.Cmd {
+	return shellPushCmd(pwshDialect())
+}
+
+func ShellPwshPopCmd() warg.Cmd {
+	return shellPopCmd(pwshDialect())
+}
+
+func ShellPwshStackCmd() warg.Cmd {
+	return shellStackCmd()
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"slices"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+
+	"go.bbkane.com/warg/value/scalar"
+)
+
+// `shell zsh reload` re-exports the active envs after database edits. The zsh
+// init script can run it from a precmd hook (see shell zsh init
+// --auto-reload), which compares the database file's mtime to the one seen at
+// the previous prompt, so enventory only runs after something changed.
+
+func shellReloadCmd(d shellDialect) warg.Cmd {
+	return warg.NewCmd(
+		"Update exported vars to match the database",
+		withSetup(func(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+			return shellReloadRun(ctx, es, cmdCtx, d)
+		}),
+		warg.NewCmdFlag(
+			"--dir",
+			"Current directory",
+			scalar.String(
+				scalar.Default(cwd),
+			),
+			warg.Required(),
+		),
+		warg.CmdFlagMap(hierarchicalFlagMap()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+// shellReloadRun transitions from the active envs to themselves, so only vars
+// whose database values changed since they were exported are written. The
+// active envs are the directory's, then ones exported with `export`, then the
+// stack. Envs the directory no longer maps to are left.
+func shellReloadRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext, d shellDialect) error {
+	pc := newPathCanonicalizer(cmdCtx)
+	dir := pc.Dir(cmdCtx.Flags["--dir"].(string))
+	hierarchical := cmdCtx.Flags["--hierarchical"].(bool)
+	lookupEnv := lookupEnvFromCtx(cmdCtx)
+
+	oldDirEnvNames, err := activeDirEnvNames(ctx, es, pc, lookupEnv, dir, hierarchical)
+	if err != nil {
+		return err
+	}
+	newDirEnvNames, err := dirEnvNames(ctx, es, pc, dir, hierarchical)
+	if err != nil {
+		return err
+	}
+	stack := readStack(lookupEnv)
+	exported, _ := readExported(lookupEnv)
+	exportedEnvNames := []string{}
+	for _, name := range exported.envNames() {
+		if !slices.Contains(oldDirEnvNames, name) && !slices.Contains(newDirEnvNames, name) && !slices.Contains(stack, name) {
+			exportedEnvNames = append(exportedEnvNames, name)
+		}
+	}
+	dirEnvsChange, err := dirEnvsChanges(ctx, es, lookupEnv, newDirEnvNames)
+	if err != nil {
+		return err
+	}
+	return writeEnvTransition(
+		ctx, es, cmdCtx, d,
+		slices.Concat(oldDirEnvNames, exportedEnvNames, stack),
+		slices.Concat(newDirEnvNames, exportedEnvNames, stack),
+		dirEnvsChange,
+	)
+}
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/completion"
+	"go.bbkane.com/warg/value/scalar"
+)
+
+// This file resolves a directory to the envs `shell <shell> chdir` activates
+// for it, and merges their vars.
+
+func hierarchicalFlagMap() warg.FlagMap {
+	return warg.FlagMap{
+		"--hierarchical": warg.NewFlag(
+			"Activate the envs of every ancestor directory too, with deeper envs overriding shallower ones",
+			scalar.Bool(
+				scalar.Default(false),
+			),
+			warg.EnvVars("ENVENTORY_HIERARCHICAL"),
+			warg.Required(),
+		),
+	}
+}
+
+// dirChain returns the directories whose envs apply to dir, from lowest to
+// highest precedence. Without hierarchical, that's just dir itself.
+func dirChain(dir string, hierarchical bool) []string {
+	if !hierarchical || !filepath.IsAbs(dir) {
+		return []string{dir}
+	}
+	dirs := []string{}
+	for current := filepath.Clean(dir); ; current = filepath.Dir(current) {
+		dirs = append(dirs, current)
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+	slices.Reverse(dirs)
+	return dirs
+}
+
+// dirEnv is an env that applies to a directory
+type dirEnv struct {
+	EnvName string
+	// Reason describes why the env applies
+	Reason string
+}
+
+// dirEnvs returns the envs that might apply to dir, from lowest to highest
+// precedence. The env bound to the git repo enclosing dir comes first. Then,
+// for each directory in the chain, envs with matching patterns come before
+// the env with the directory as an env path, which comes before the env named
+// after the directory. The envs might not exist. dir must be canonicalized
+// with pc.
+func dirEnvs(ctx context.Context, es models.Service, pc pathCanonicalizer, dir string, hierarchical bool) ([]dirEnv, error) {
+	envs := []dirEnv{}
+
+	repo, err := findGitRepo(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not find git repo: %s: %w", dir, err)
+	}
+	if repo != nil && repo.Key != "" {
+		repoEnvName, err := es.EnvRepoFind(ctx, repo.Key)
+		if err != nil && !errors.Is(err, models.ErrEnvRepoNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			envs = append(envs, dirEnv{EnvName: repoEnvName, Reason: "repo " + repo.Key})
+		}
+	}
+
+	for _, d := range dirChain(dir, hierarchical) {
+		dName := pc.DirEnvName(d)
+		patterns, err := es.EnvPatternMatch(ctx, models.EnvPatternMatchArgs{
+			Paths:           slices.Compact([]string{d, dName}),
+			CaseInsensitive: pc.caseInsensitive,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not match env patterns: %s: %w", d, err)
+		}
+		for _, p := range patterns {
+			envs = append(envs, dirEnv{EnvName: p.EnvName, Reason: fmt.Sprintf("%s %s matches %s", p.Kind, p.Pattern, dName)})
+		}
+		pathEnvName, err := es.EnvPathFind(ctx, dName)
+		if err != nil && !errors.Is(err, models.ErrEnvPathNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			envs = append(envs, dirEnv{EnvName: pathEnvName, Reason: "path " + dName})
+		}
+		envs = append(envs, dirEnv{EnvName: dName, Reason: "name"})
+	}
+
+	// an env can be found more than once - only its highest precedence
+	// occurrence counts
+	seen := map[string]bool{}
+	deduped := []dirEnv{}
+	for _, e := range slices.Backward(envs) {
+		if !seen[e.EnvName] {
+			seen[e.EnvName] = true
+			deduped = append(deduped, e)
+		}
+	}
+	slices.Reverse(deduped)
+	return deduped, nil
+}
+
+// resolveEnvName returns name if an env has that name, and otherwise the env
+// with name as an env path. It returns a wrapped models.ErrEnvNotFound if
+// neither exists.
+func resolveEnvName(ctx context.Context, es models.Service, name string) (string, error) {
+	_, err := es.EnvShow(ctx, name)
+	if err == nil {
+		return name, nil
+	}
+	if !errors.Is(err, models.ErrEnvNotFound) {
+		return "", fmt.Errorf("could not show env: %s: %w", name, err)
+	}
+	envName, err := es.EnvPathFind(ctx, name)
+	if errors.Is(err, models.ErrEnvPathNotFound) {
+		return "", fmt.Errorf("could not find env with name or path: %s: %w", name, models.ErrEnvNotFound)
+	}
+	if err != nil {
+		return "", err
+	}
+	return envName, nil
+}
+
+// completeExistingEnvNameOrPath completes env names and env paths
+func completeExistingEnvNameOrPath(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) (*completion.Candidates, error) {
+	candidates, err := completeExistingEnvName(ctx, es, cmdCtx)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := es.EnvPathListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list env paths for completion: %w", err)
+	}
+	for _, p := range paths {
+		candidates.Values = append(candidates.Values, completion.Candidate{
+			Name:        p.Path,
+			Description: "path for env " + p.EnvName,
+		})
+	}
+	return candidates, nil
+}
+
+// shellEnvNameFlag is envNameFlag, but it also accepts env paths
+func shellEnvNameFlag() warg.Flag {
+	return warg.NewFlag(
+		"Environment name or env path",
+		scalar.String(
+			scalar.Default(cwd),
+		),
+		warg.Required(),
+		warg.FlagCompletions(withEnvServiceCompletions(
+			completeExistingEnvNameOrPath)),
+	)
+}
+
+// dirEnvNames returns the names of dirEnvs
+func dirEnvNames(ctx context.Context, es models.Service, pc pathCanonicalizer, dir string, hierarchical bool) ([]string, error) {
+	envs, err := dirEnvs(ctx, es, pc, dir, hierarchical)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(envs))
+	for _, e := range envs {
+		names = append(names, e.EnvName)
+	}
+	return names, nil
+}
+
+// resolvedVar is an exportable along with the env providing it
+type resolvedVar struct {
+	Env string
+	// Owner owns the var or ref. It's an env Env includes if they differ.
+	Owner string
+	// Type is "var" or "var_ref"
+	Type  string
+	Value string
+	// Enabled is false if either the exportable or its env is disabled
+	Enabled bool
+	// Scope lists the commands a scoped var is passed to instead of the shell
+	Scope []string
+	// Exportable is what the env lists. Its Value is the command for command
+	// vars, while Value is the command's output once resolveVarValues runs.
+	Exportable models.EnvExportable
+}
+
+// resolveExportables merges the exportables of the envs in envNames, ordered
+// from lowest to highest precedence. Envs that don't exist are skipped.
+// Disabled exportables never override enabled ones. Command vars aren't run
+// and templates aren't expanded; use resolveVarValues for that.
+func resolveExportables(ctx context.Context, es models.Service, envNames []string) (map[string]resolvedVar, error) {
+	resolved := map[string]resolvedVar{}
+	for _, envName := range envNames {
+		exportables, err := es.EnvExportableList(ctx, envName)
+		if errors.Is(err, models.ErrEnvNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not list env exportables: %s: %w", envName, err)
+		}
+		if len(exportables) == 0 {
+			continue
+		}
+		env, err := es.EnvShow(ctx, envName)
+		if err != nil {
+			return nil, fmt.Errorf("could not show env: %s: %w", envName, err)
+		}
+		for _, e := range exportables {
+			rv := resolvedVar{Env: envName, Owner: e.EnvName, Type: e.Type, Value: e.Value, Enabled: env.Enabled && e.Enabled, Scope: e.Scope, Exportable: e}
+			existing, exists := resolved[e.Name]
+			if !exists || rv.Enabled || !existing.Enabled {
+				resolved[e.Name] = rv
+			}
+		}
+	}
+	return resolved, nil
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+)
+
+// Scoped vars (var create --scope gh) are never exported into the shell.
+// export and chdir record them in shellExportedVar instead, and the zsh init
+// script wraps each scoped command in a function running
+// `enventory exec --scoped`, which looks their values up.
+
+//nolint:gochecknoglobals // compiled once
+var commandName = regexp.MustCompile(`^[a-zA-Z0-9_.+-]+$`)
+
+// validateScope checks that scope holds plain command names, since the zsh
+// init script defines functions named after them
+func validateScope(scope []string) error {
+	for _, c := range scope {
+		if !commandName.MatchString(c) {
+			return fmt.Errorf("scope must be a comma-separated list of command names: %q", c)
+		}
+	}
+	return nil
+}
+
+// inScope reports whether a var with scope is passed to command. Vars without
+// a scope are passed to every command.
+func inScope(scope []string, command string) bool {
+	return len(scope) == 0 || slices.Contains(scope, filepath.Base(command))
+}
+
+// recordedScopedVars returns the values of the scoped vars recorded in
+// shellExportedVar whose scope includes command
+func recordedScopedVars(ctx context.Context, cmdCtx warg.CmdContext, es models.Service, command string) ([]kv, error) {
+	exported, _ := readExported(lookupEnvFromCtx(cmdCtx))
+	exportablesByEnv := map[string][]models.EnvExportable{}
+	vars := []kv{}
+	for _, name := range slices.Sorted(maps.Keys(exported)) {
+		ev := exported[name]
+		if ev.Scope == "" || !inScope(strings.Split(ev.Scope, ","), command) {
+			continue
+		}
+		exportables, cached := exportablesByEnv[ev.Env]
+		if !cached {
+			var err error
+			exportables, err = es.EnvExportableList(ctx, ev.Env)
+			if err != nil {
+				return nil, fmt.Errorf("could not list exportable env vars: %s: %w", ev.Env, err)
+			}
+			// every var is resolved, since scoped vars' templates can
+			// reference unscoped ones. Command output is usually cached.
+			var failed map[string]error
+			exportables, failed = resolveExportableValues(ctx, es, cmdCtx, exportables)
+			// only failures command would have used are worth mentioning
+			maps.DeleteFunc(failed, func(name string, _ error) bool {
+				return exported[name].Scope == "" || !inScope(strings.Split(exported[name].Scope, ","), command)
+			})
+			warnFailedVars(cmdCtx, failed)
+			exportablesByEnv[ev.Env] = exportables
+		}
+		// the var may have been deleted, disabled, or rescoped since it was
+		// recorded
+		for _, e := range exportables {
+			if e.Name == name && e.Enabled && len(e.Scope) > 0 && inScope(e.Scope, command) {
+				vars = append(vars, kv{Name: e.Name, Value: e.Value})
+			}
+		}
+	}
+	return vars, nil
+}
+
+func shellScopedCommandsCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Print the commands any var is scoped to, one per line",
+		withSetup(shellScopedCommandsRun),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func shellScopedCommandsRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	envs, err := es.EnvList(ctx, models.EnvListArgs{Expr: nil})
+	if err != nil {
+		return fmt.Errorf("could not list envs: %w", err)
+	}
+	commands := map[string]bool{}
+	for _, env := range envs {
+		vars, err := es.VarList(ctx, env.Name)
+		if err != nil {
+			return fmt.Errorf("could not list vars: %s: %w", env.Name, err)
+		}
+		for _, v := range vars {
+			for _, c := range v.Scope {
+				commands[c] = true
+			}
+		}
+	}
+	for _, c := range slices.Sorted(maps.Keys(commands)) {
+		fmt.Fprintln(cmdCtx.Stdout, c)
+	}
+	return nil
+}
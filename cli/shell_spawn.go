@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+
+	"go.bbkane.com/warg/value/scalar"
+	"go.bbkane.com/warg/value/slice"
+)
+
+// shellSpawnedVar is set in shells started by `shell spawn` to the
+// comma-separated names of the envs they were started with
+const shellSpawnedVar = "ENVENTORY_SPAWNED"
+
+const shellSpawnCmdHelpLong = `Start an interactive shell with the merged vars of one or more envs. Vars from
+later envs override earlier ones. Exiting the shell discards them, so this
+works in shells without hook support.
+
+The shell gets ` + shellSpawnedVar + ` set to the env names. If PS1 is exported, it's
+prefixed with them too. Otherwise, use ` + shellSpawnedVar + ` in your prompt.
+
+Arguments after -- are passed to the shell instead of -i.
+
+Examples:
+
+enventory shell spawn --env work --env ~/proj
+
+# run a script instead of an interactive shell
+enventory shell spawn --env work -- -c 'echo $WORK_VAR'`
+
+func ShellSpawnCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Start a shell with envs exported",
+		withSetup(shellSpawnRun),
+		warg.CmdHelpLong(shellSpawnCmdHelpLong),
+		warg.AllowForwardedArgs(),
+		warg.NewCmdFlag(
+			"--env",
+			"Envs or env paths to export. Vars from later envs override earlier ones",
+			slice.String(),
+			warg.Required(),
+			warg.FlagCompletions(withEnvServiceCompletions(
+				completeExistingEnvNameOrPath)),
+		),
+		warg.NewCmdFlag(
+			"--shell",
+			"Shell to start",
+			scalar.String(
+				scalar.Default("/bin/sh"),
+			),
+			warg.EnvVars("SHELL"),
+			warg.Required(),
+		),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func shellSpawnRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	shell := cmdCtx.Flags["--shell"].(string)
+
+	envNames := []string{}
+	for _, name := range cmdCtx.Flags["--env"].([]string) {
+		envName, err := resolveEnvName(ctx, es, canonicalEnvName(cmdCtx, name))
+		if err != nil {
+			return err
+		}
+		envNames = append(envNames, envName)
+	}
+
+	resolved, err := resolveExportables(ctx, es, envNames)
+	if err != nil {
+		return fmt.Errorf("could not resolve envs: %w", err)
+	}
+	failed := resolveVarValues(ctx, es, cmdCtx, resolved)
+	warnFailedVars(cmdCtx, failed)
+
+	environ := os.Environ()
+	for _, name := range slices.Sorted(maps.Keys(resolved)) {
+		rv := resolved[name]
+		// scoped vars only reach their commands through the zsh wrappers
+		if rv.Enabled && len(rv.Scope) == 0 {
+			environ = append(environ, name+"="+rv.Value)
+		}
+	}
+	spawned := strings.Join(envNames, ",")
+	environ = append(environ, shellSpawnedVar+"="+spawned)
+	if ps1, exists := os.LookupEnv("PS1"); exists {
+		environ = append(environ, "PS1=("+spawned+") "+ps1)
+	}
+
+	args := cmdCtx.ForwardedArgs
+	if len(args) == 0 {
+		args = []string{"-i"}
+	}
+
+	cmd := exec.Command(shell, args...)
+	cmd.Env = environ
+	cmd.Stdin = os.Stdin // TODO: update this if I add a cmdCtx.Stdin to warg
+	cmd.Stdout = cmdCtx.Stdout
+	cmd.Stderr = cmdCtx.Stderr
+	return cmd.Run()
+}
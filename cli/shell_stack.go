@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+
+	"go.bbkane.com/warg/value/scalar"
+)
+
+// This file holds the `shell <shell> push/pop/stack` commands. Pushed envs are
+// kept in shellStackVar, so each shell session has its own stack, and are
+// exported on top of the current directory's envs.
+
+func shellStackDirFlagMap() warg.FlagMap {
+	return warg.FlagMap{
+		"--dir": warg.NewFlag(
+			"Current directory. Its envs stay exported under the stack",
+			scalar.String(
+				scalar.Default(cwd),
+			),
+			warg.Required(),
+		),
+	}
+}
+
+func shellPushCmd(d shellDialect) warg.Cmd {
+	return warg.NewCmd(
+		"Export an env on top of the current ones until it's popped",
+		withSetup(func(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+			return shellPushRun(ctx, es, cmdCtx, d)
+		}),
+		warg.CmdFlag("--env", shellEnvNameFlag()),
+		warg.CmdFlagMap(shellStackDirFlagMap()),
+		warg.CmdFlagMap(hierarchicalFlagMap()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func shellPopCmd(d shellDialect) warg.Cmd {
+	return warg.NewCmd(
+		"Unexport the most recently pushed env",
+		withSetup(func(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+			return shellPopRun(ctx, es, cmdCtx, d)
+		}),
+		warg.CmdFlagMap(shellStackDirFlagMap()),
+		warg.CmdFlagMap(hierarchicalFlagMap()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+	)
+}
+
+func shellStackCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Print pushed envs, most recent first",
+		shellStackRun,
+	)
+}
+
+// stackTransition writes the script moving from the current stack to
+// newStack, with the envs of the --dir flag's directory underneath both
+func stackTransition(ctx context.Context, es models.Service, cmdCtx warg.CmdContext, d shellDialect, stack []string, newStack []string) error {
+	pc := newPathCanonicalizer(cmdCtx)
+	dir := pc.Dir(cmdCtx.Flags["--dir"].(string))
+	hierarchical := cmdCtx.Flags["--hierarchical"].(bool)
+
+	lookupEnv := lookupEnvFromCtx(cmdCtx)
+
+	oldEnvNames, err := activeDirEnvNames(ctx, es, pc, lookupEnv, dir, hierarchical)
+	if err != nil {
+		return err
+	}
+	envNames, err := dirEnvNames(ctx, es, pc, dir, hierarchical)
+	if err != nil {
+		return err
+	}
+	dirEnvsChange, err := dirEnvsChanges(ctx, es, lookupEnv, envNames)
+	if err != nil {
+		return err
+	}
+	return writeEnvTransition(
+		ctx, es, cmdCtx, d,
+		slices.Concat(oldEnvNames, stack),
+		slices.Concat(envNames, newStack),
+		slices.Concat([]shellChange{stackChange(newStack)}, dirEnvsChange),
+	)
+}
+
+func shellPushRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext, d shellDialect) error {
+	envName, err := resolveEnvName(ctx, es, envNameArg(cmdCtx, "--env"))
+	if err != nil {
+		return err
+	}
+	stack := readStack(lookupEnvFromCtx(cmdCtx))
+	newStack := append(slices.Clone(stack), envName)
+	return stackTransition(ctx, es, cmdCtx, d, stack, newStack)
+}
+
+func shellPopRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext, d shellDialect) error {
+	stack := readStack(lookupEnvFromCtx(cmdCtx))
+	if len(stack) == 0 {
+		return errors.New("no envs pushed")
+	}
+	return stackTransition(ctx, es, cmdCtx, d, stack, stack[:len(stack)-1])
+}
+
+func shellStackRun(cmdCtx warg.CmdContext) error {
+	stack := readStack(lookupEnvFromCtx(cmdCtx))
+	for _, envName := range slices.Backward(stack) {
+		fmt.Fprintln(cmdCtx.Stdout, envName)
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// Generated scripts keep state between enventory invocations in exported
+// shell variables, so each invocation can read it from its environment.
+
+// shellShadowedVar holds a JSON object mapping var names to the values they
+// had in the shell before enventory overwrote them.
+const shellShadowedVar = "ENVENTORY_SHADOWED"
+
+// shellExportedVar holds a JSON object mapping the names of vars enventory
+// exported to an exportedVar.
+const shellExportedVar = "ENVENTORY_EXPORTED"
+
+// exportedVar records which env exported a var. It holds a fingerprint instead
+// of the value so secrets aren't copied into another env var.
+type exportedVar struct {
+	Env         string `json:"env"`
+	Fingerprint string `json:"fp"`
+	// Scope is the comma-separated list of commands a scoped var is passed
+	// to. Scoped vars are recorded but not exported into the shell.
+	Scope string `json:"scope,omitempty"`
+	// Shadowed is the record this one replaced when an env pushed or entered
+	// on top of another exported the same var. It's restored along with the
+	// var's value (see shadowValues) when this env is left.
+	Shadowed *exportedVar `json:"shadowed,omitempty"`
+}
+
+// equal reports whether ev and other record the same export, including what
+// they shadow
+func (ev exportedVar) equal(other exportedVar) bool {
+	if ev.Env != other.Env || ev.Fingerprint != other.Fingerprint || ev.Scope != other.Scope {
+		return false
+	}
+	if ev.Shadowed == nil || other.Shadowed == nil {
+		return ev.Shadowed == other.Shadowed
+	}
+	return ev.Shadowed.equal(*other.Shadowed)
+}
+
+type exportedVars map[string]exportedVar
+
+// namesInEnv returns the sorted names of vars exported by envName
+func (e exportedVars) namesInEnv(envName string) []string {
+	names := []string{}
+	for name, ev := range e {
+		if ev.Env == envName {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// envNames returns the sorted names of the envs that exported vars
+func (e exportedVars) envNames() []string {
+	names := []string{}
+	for _, ev := range e {
+		if !slices.Contains(names, ev.Env) {
+			names = append(names, ev.Env)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// fingerprint identifies a value without revealing it
+func fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:8])
+}
+
+// readExported reads shellExportedVar. The bool is false if it isn't set,
+// meaning the shell hasn't recorded any exports and callers should fall back
+// to reading envs from the database.
+func readExported(lookupEnv LookupEnvFunc) (exportedVars, bool) {
+	exported := exportedVars{}
+	val, exists := lookupEnv(shellExportedVar)
+	if !exists || val == "" {
+		return exported, false
+	}
+	err := json.Unmarshal([]byte(val), &exported)
+	if err != nil {
+		return exportedVars{}, false
+	}
+	return exported, true
+}
+
+// readShadowed reads shellShadowedVar. A missing or invalid value is treated
+// as empty, as there's nothing better to do from inside a chpwd hook.
+func readShadowed(lookupEnv LookupEnvFunc) map[string]string {
+	shadowed := map[string]string{}
+	val, exists := lookupEnv(shellShadowedVar)
+	if !exists || val == "" {
+		return shadowed
+	}
+	err := json.Unmarshal([]byte(val), &shadowed)
+	if err != nil {
+		return map[string]string{}
+	}
+	return shadowed
+}
+
+// shadowValues updates todo so that removing a var restores the value it had
+// before enventory overwrote it, and saves the current values of vars todo
+// is about to overwrite. managed holds the names of vars that enventory
+// exported, so the current values are not worth saving.
+//
+// It returns the updated todo and a silent change to shellShadowedVar if its
+// contents changed.
+func shadowValues(todo computeExportChangesResult, managed map[string]bool, lookupEnv LookupEnvFunc) (computeExportChangesResult, []shellChange) {
+	shadowed := readShadowed(lookupEnv)
+	changed := false
+
+	for _, kv := range slices.Concat(todo.ToChange, todo.Unchanged) {
+		if managed[kv.Name] {
+			continue
+		}
+		if _, exists := shadowed[kv.Name]; exists {
+			continue
+		}
+		current, _ := lookupEnv(kv.Name)
+		shadowed[kv.Name] = current
+		changed = true
+	}
+
+	toRemove := make([]kv, 0, len(todo.ToRemove))
+	for _, removed := range todo.ToRemove {
+		original, exists := shadowed[removed.Name]
+		if !exists {
+			toRemove = append(toRemove, removed)
+			continue
+		}
+		delete(shadowed, removed.Name)
+		changed = true
+
+		current, _ := lookupEnv(removed.Name)
+		restored := kv{Name: removed.Name, Value: original}
+		if current == original {
+			todo.Unchanged = append(todo.Unchanged, restored)
+		} else {
+			todo.ToChange = append(todo.ToChange, restored)
+		}
+	}
+	todo.ToRemove = toRemove
+	cmp := func(a, b kv) int {
+		return strings.Compare(a.Name, b.Name)
+	}
+	slices.SortFunc(todo.ToChange, cmp)
+	slices.SortFunc(todo.Unchanged, cmp)
+
+	if !changed {
+		return todo, nil
+	}
+	return todo, []shellChange{stateChange(shellShadowedVar, shadowed)}
+}
+
+// stateChange returns a silent change exporting m as JSON to name, or
+// unsetting name if m is empty
+func stateChange[M ~map[string]T, T any](name string, m M) shellChange {
+	if len(m) == 0 {
+		return shellChange{Op: shellChangeOpRemove, Name: name, Value: "", Silent: true}
+	}
+	// Marshalling a map with string keys can't fail
+	buf, _ := json.Marshal(m)
+	return shellChange{Op: shellChangeOpChange, Name: name, Value: string(buf), Silent: true}
+}
+
+// shellActiveVar holds the comma-separated names of the envs in
+// shellExportedVar, so prompts can show them without running enventory.
+const shellActiveVar = "ENVENTORY_ACTIVE"
+
+// exportedChanges returns silent changes saving exported to shellExportedVar
+// and its env names to shellActiveVar. shellExportedVar is set to {} rather
+// than unset once nothing is exported, so the shell stays tracked and later
+// commands don't fall back to reading envs from the database.
+func exportedChanges(exported exportedVars) []shellChange {
+	active := shellChange{Op: shellChangeOpRemove, Name: shellActiveVar, Value: "", Silent: true}
+	if len(exported) > 0 {
+		active = shellChange{Op: shellChangeOpChange, Name: shellActiveVar, Value: strings.Join(exported.envNames(), ","), Silent: true}
+	}
+	// Marshalling a map with string keys can't fail
+	buf, _ := json.Marshal(exported)
+	return []shellChange{
+		{Op: shellChangeOpChange, Name: shellExportedVar, Value: string(buf), Silent: true},
+		active,
+	}
+}
+
+// shellStackVar holds a JSON array of the env names pushed with
+// `shell <shell> push`, from the bottom of the stack to the top.
+const shellStackVar = "ENVENTORY_STACK"
+
+// readStack reads shellStackVar. A missing or invalid value is treated as an
+// empty stack.
+func readStack(lookupEnv LookupEnvFunc) []string {
+	stack := []string{}
+	val, exists := lookupEnv(shellStackVar)
+	if !exists || val == "" {
+		return stack
+	}
+	err := json.Unmarshal([]byte(val), &stack)
+	if err != nil {
+		return []string{}
+	}
+	return stack
+}
+
+// stackChange returns a silent change exporting stack as JSON to
+// shellStackVar, or unsetting it if stack is empty
+func stackChange(stack []string) shellChange {
+	if len(stack) == 0 {
+		return shellChange{Op: shellChangeOpRemove, Name: shellStackVar, Value: "", Silent: true}
+	}
+	// Marshalling a slice of strings can't fail
+	buf, _ := json.Marshal(stack)
+	return shellChange{Op: shellChangeOpChange, Name: shellStackVar, Value: string(buf), Silent: true}
+}
+
+// shellDirEnvsVar holds a JSON array of the env names activated for the
+// shell's directory, from lowest to highest precedence. chdir leaves these
+// envs rather than recomputing them from the old directory, since the
+// database may no longer map it to them (a pattern, path, or repo was
+// removed, or the env was renamed).
+const shellDirEnvsVar = "ENVENTORY_DIR_ENVS"
+
+// readDirEnvs reads shellDirEnvsVar. The bool is false if it isn't set or is
+// invalid, meaning callers should fall back to the directory's current envs.
+func readDirEnvs(lookupEnv LookupEnvFunc) ([]string, bool) {
+	envNames := []string{}
+	val, exists := lookupEnv(shellDirEnvsVar)
+	if !exists || val == "" {
+		return envNames, false
+	}
+	err := json.Unmarshal([]byte(val), &envNames)
+	if err != nil {
+		return []string{}, false
+	}
+	return envNames, true
+}
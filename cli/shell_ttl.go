@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.bbkane.com/warg"
+
+	"go.bbkane.com/warg/value/scalar"
+)
+
+// This file holds `shell zsh export --ttl`, which exports an env until a
+// deadline, and `shell zsh expire`, which the init script's precmd hook runs
+// once the deadline passes. Nothing keeps running in the background; the hook
+// compares shellDeadlineVar to the current time before each prompt.
+
+// shellExpiresVar holds a JSON object mapping the names of envs exported with
+// a TTL to their deadlines in Unix seconds.
+const shellExpiresVar = "ENVENTORY_EXPIRES"
+
+// shellDeadlineVar holds the earliest deadline in shellExpiresVar in Unix
+// seconds, so the shell can check it without parsing JSON.
+const shellDeadlineVar = "ENVENTORY_DEADLINE"
+
+func shellTTLFlagMap() warg.FlagMap {
+	return warg.FlagMap{
+		"--ttl": warg.NewFlag(
+			"Unset the exported vars (restoring the values they replaced) after this long. Use https://pkg.go.dev/time#Duration to build it",
+			scalar.Duration(),
+		),
+	}
+}
+
+func shellNowFlagMap() warg.FlagMap {
+	return warg.FlagMap{
+		"--now": warg.NewFlag(
+			"Current time. Useful for testing",
+			scalar.DateTimeRFC3339(
+				scalar.Default(time.Now()),
+			),
+			warg.Required(),
+		),
+	}
+}
+
+// readExpires reads shellExpiresVar. A missing or invalid value is treated as
+// empty.
+func readExpires(lookupEnv LookupEnvFunc) map[string]int64 {
+	expires := map[string]int64{}
+	val, exists := lookupEnv(shellExpiresVar)
+	if !exists || val == "" {
+		return expires
+	}
+	err := json.Unmarshal([]byte(val), &expires)
+	if err != nil {
+		return map[string]int64{}
+	}
+	return expires
+}
+
+// expiresChanges returns silent changes saving expires to shellExpiresVar and
+// its earliest deadline to shellDeadlineVar, or unsetting both if it's empty
+func expiresChanges(expires map[string]int64) []shellChange {
+	if len(expires) == 0 {
+		return []shellChange{
+			stateChange(shellExpiresVar, expires),
+			{Op: shellChangeOpRemove, Name: shellDeadlineVar, Value: "", Silent: true},
+		}
+	}
+	deadline := slices.Min(slices.Collect(maps.Values(expires)))
+	return []shellChange{
+		stateChange(shellExpiresVar, expires),
+		{Op: shellChangeOpChange, Name: shellDeadlineVar, Value: strconv.FormatInt(deadline, 10), Silent: true},
+	}
+}
+
+func shellExpireCmd(d shellDialect) warg.Cmd {
+	return warg.NewCmd(
+		"Unexport envs exported with --ttl whose deadline passed",
+		func(cmdCtx warg.CmdContext) error {
+			return shellExpireRun(cmdCtx, d)
+		},
+		warg.CmdFlagMap(shellNowFlagMap()),
+	)
+}
+
+// shellExpireRun unsets the vars and aliases of expired envs, restoring the
+// values the vars replaced. It doesn't need the database since the shell recorded what the
+// envs exported.
+func shellExpireRun(cmdCtx warg.CmdContext, d shellDialect) error {
+	now := cmdCtx.Flags["--now"].(time.Time)
+	lookupEnv := lookupEnvFromCtx(cmdCtx)
+
+	expires := readExpires(lookupEnv)
+	exported, _ := readExported(lookupEnv)
+	before := maps.Clone(exported)
+
+	oldKVs := map[string]string{}
+	expired := []string{}
+	for _, envName := range slices.Sorted(maps.Keys(expires)) {
+		if expires[envName] > now.Unix() {
+			continue
+		}
+		expired = append(expired, envName)
+		delete(expires, envName)
+		for _, name := range exported.namesInEnv(envName) {
+			oldKVs[name] = ""
+			delete(exported, name)
+		}
+	}
+
+	// always rewrite the deadline so the hook doesn't call this again until
+	// the next env expires
+	todo := computeExportChanges(oldKVs, nil, lookupEnv)
+	todo, stateChanges := shadowValues(todo, nil, lookupEnv)
+	if !maps.EqualFunc(before, exported, exportedVar.equal) {
+		stateChanges = append(stateChanges, exportedChanges(exported)...)
+	}
+	aliasChanges := aliasTransition(readAliases(lookupEnv), expired, nil)
+	d.WriteScript(cmdCtx.Stdout, cmdCtx.App.Name, slices.Concat(changesFromResult(todo), aliasChanges, stateChanges, expiresChanges(expires)))
+	return nil
+}
+
+// ttlChanges returns the state changes to record exporting envName until
+// now + --ttl, or to forget its deadline if --ttl wasn't passed (as when
+// unexporting). exportedKVs are the vars being exported, and prevNames are the
+// vars envName exported before, whose current values are enventory's and
+// aren't worth restoring later.
+func ttlChanges(cmdCtx warg.CmdContext, envName string, exportedKVs map[string]string, prevNames []string) []shellChange {
+	lookupEnv := lookupEnvFromCtx(cmdCtx)
+	ttl := time.Duration(0)
+	if val, exists := cmdCtx.Flags["--ttl"]; exists {
+		ttl = val.(time.Duration)
+	}
+
+	expires := readExpires(lookupEnv)
+	before := maps.Clone(expires)
+	changes := []shellChange{}
+	if ttl > 0 {
+		now := cmdCtx.Flags["--now"].(time.Time)
+		expires[envName] = now.Add(ttl).Unix()
+
+		// save the values the export replaces so expiring restores them
+		managed := map[string]bool{}
+		for _, name := range prevNames {
+			managed[name] = true
+		}
+		todo := computeExportChanges(nil, exportedKVs, lookupEnv)
+		_, changes = shadowValues(todo, managed, lookupEnv)
+	} else {
+		delete(expires, envName)
+	}
+	if !maps.Equal(before, expires) {
+		changes = append(changes, expiresChanges(expires)...)
+	}
+	return changes
+}
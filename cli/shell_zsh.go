@@ -1,16 +1,11 @@
 package cli
 
 import (
-	"context"
-	"errors"
 	"fmt"
-	"os"
-	"slices"
-	"strings"
 
 	"al.essio.dev/pkg/shellescape"
-	"go.bbkane.com/enventory/models"
 	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/path"
 
 	"go.bbkane.com/warg/value/scalar"
 )
@@ -37,6 +32,23 @@ func ShellZshInitCmd() warg.Cmd {
 			),
 			warg.Required(),
 		),
+		warg.NewCmdFlag(
+			"--auto-reload",
+			"Add a precmd hook that runs shell zsh reload when the database file changes",
+			scalar.Bool(
+				scalar.Default(false),
+			),
+			warg.Required(),
+		),
+		warg.NewCmdFlag(
+			"--scoped-commands",
+			"Wrap commands that have scoped vars (var create --scope) in functions. Lists the commands from the database when the shell starts",
+			scalar.Bool(
+				scalar.Default(false),
+			),
+			warg.Required(),
+		),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
 	)
 }
 
@@ -44,6 +56,11 @@ func shellZshInitRun(cmdCtx warg.CmdContext) error {
 
 	printAutoload := cmdCtx.Flags["--print-autoload"].(bool)
 	chpwdStrategy := cmdCtx.Flags["--chpwd-strategy"].(string)
+	autoReload := cmdCtx.Flags["--auto-reload"].(bool)
+	scopedCommands := cmdCtx.Flags["--scoped-commands"].(bool)
+	dbPath := cmdCtx.Flags["--db-path"].(path.Path).MustExpand()
+	// pass the database the script was made for to everything it runs
+	dbPathArg := "--db-path " + shellescape.Quote(dbPath)
 
 	prelude := `
 # https://github.com/bbkane/enventory/
@@ -67,269 +84,113 @@ autoload -Uz add-zsh-hook
 	case "v0.0.19":
 		chpwdHook = `
 add-zsh-hook -Uz chpwd (){
-    eval $(enventory shell zsh unexport --env "$OLDPWD" --no-env-no-problem true)
-    eval $(enventory shell zsh export --env "$PWD" --no-env-no-problem true)
+    eval $(enventory shell zsh unexport ` + dbPathArg + ` --env "$OLDPWD" --no-env-no-problem true)
+    eval $(enventory shell zsh export ` + dbPathArg + ` --env "$PWD" --no-env-no-problem true)
 }
 `
 	case "v0.0.20":
 		chpwdHook = `
 add-zsh-hook -Uz chpwd (){
-    eval $(enventory shell zsh chdir --old "$OLDPWD" --new "$PWD")
+    eval $(enventory shell zsh chdir ` + dbPathArg + ` --old "$OLDPWD" --new "$PWD")
 }
 `
 	}
 
 	fmt.Fprint(cmdCtx.Stdout, chpwdHook)
 
+	// the hook returns without running enventory unless an export-env --ttl
+	// deadline is set and has passed, so it's cheap before every prompt.
+	// expire only reads what the shell recorded, so it doesn't take --db-path
+	expireHook := `
+zmodload zsh/datetime
+__enventory_expire() {
+    [[ -z "$ENVENTORY_DEADLINE" ]] && return
+    (( EPOCHSECONDS < ENVENTORY_DEADLINE )) && return
+    eval $(enventory shell zsh expire)
+}
+add-zsh-hook precmd __enventory_expire
+`
+	fmt.Fprint(cmdCtx.Stdout, expireHook)
+
+	// the first prompt only records the mtime. zstat has one second
+	// resolution, so an edit in the same second as the previous prompt is
+	// missed until the next edit
+	reloadHook := `
+zmodload -F zsh/stat b:zstat
+__enventory_reload() {
+    local mtime
+    zstat -A mtime +mtime -- ` + shellescape.Quote(dbPath) + ` 2>/dev/null || return
+    if [[ -n "$__enventory_db_mtime" && "$mtime" != "$__enventory_db_mtime" ]]; then
+        eval "$(enventory shell zsh reload ` + dbPathArg + `)"
+    fi
+    __enventory_db_mtime=$mtime
+}
+add-zsh-hook precmd __enventory_reload
+`
+	if autoReload {
+		fmt.Fprint(cmdCtx.Stdout, reloadHook)
+	}
+
+	// vars scoped to a command (var create --scope) are only passed to it.
+	// Listing the commands reads the database, so it's opt-in to keep shell
+	// startup fast
+	scopedWrappers := `
+__enventory_db_path=` + shellescape.Quote(dbPath) + `
+for __enventory_cmd in ${(f)"$(enventory shell zsh scoped-commands --db-path "$__enventory_db_path")"}; do
+    eval "${__enventory_cmd}() { enventory exec --db-path ${(q)__enventory_db_path} --scoped true -- ${__enventory_cmd} \"\$@\" }"
+done
+unset __enventory_cmd __enventory_db_path
+`
+	if scopedCommands {
+		fmt.Fprint(cmdCtx.Stdout, scopedWrappers)
+	}
+
 	exportEnv := `
-export-env() { eval $(enventory shell zsh export --env "$1" --no-env-no-problem true) }
-unexport-env() { eval $(enventory shell zsh unexport --env "$1" --no-env-no-problem true) }
+export-env() { eval $(enventory shell zsh export ` + dbPathArg + ` --env "$1" --no-env-no-problem true "${@:2}") }
+unexport-env() { eval $(enventory shell zsh unexport ` + dbPathArg + ` --env "$1" --no-env-no-problem true) }
+push-env() { eval $(enventory shell zsh push ` + dbPathArg + ` --env "$1") }
+pop-env() { eval $(enventory shell zsh pop ` + dbPathArg + `) }
 `
 	fmt.Fprint(cmdCtx.Stdout, exportEnv)
 
 	return nil
 }
-
 func ShellZshExportCmd() warg.Cmd {
-	return warg.NewCmd(
-		"Print export script",
-		withSetup(shellZshExportRun),
-		warg.CmdFlag("--env", envNameFlag()),
-		warg.CmdFlagMap(timeoutFlagMap()),
-		warg.CmdFlagMap(sqliteDSNFlagMap()),
-		warg.NewCmdFlag(
-			"--no-env-no-problem",
-			"Exit without an error if the environment doesn't exit. Useful when runnng envelop on chpwd",
-			scalar.Bool(
-				scalar.Default(false),
-			),
-			warg.Required(),
-		),
+	return shellExportCmd(
+		posixDialect(),
+		warg.CmdFlagMap(shellTTLFlagMap()),
+		warg.CmdFlagMap(shellNowFlagMap()),
 	)
 }
 
-func shellZshExportRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
-	return shellZshExportUnexport(ctx, cmdCtx, es, "export")
+func ShellZshScopedCommandsCmd() warg.Cmd {
+	return shellScopedCommandsCmd()
 }
 
-func ShellZshUnexportCmd() warg.Cmd {
-	return warg.NewCmd(
-		"Print unexport script",
-		withSetup(shellZshUnexportRun),
-		warg.CmdFlag("--env", envNameFlag()),
-		warg.CmdFlagMap(timeoutFlagMap()),
-		warg.CmdFlagMap(sqliteDSNFlagMap()),
-		warg.NewCmdFlag(
-			"--no-env-no-problem",
-			"Exit without an error if the environment doesn't exit. Useful when runnng envelop on chpwd",
-			scalar.Bool(
-				scalar.Default(false),
-			),
-			warg.Required(),
-		),
-	)
-}
-
-func shellZshUnexportRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
-	return shellZshExportUnexport(ctx, cmdCtx, es, "unexport")
+func ShellZshExpireCmd() warg.Cmd {
+	return shellExpireCmd(posixDialect())
 }
 
-func shellZshExportUnexport(ctx context.Context, cmdCtx warg.CmdContext, es models.Service, scriptType string) error {
-	envName := mustGetEnvNameArg(cmdCtx.Flags)
-	noEnvNoProblem := cmdCtx.Flags["--no-env-no-problem"].(bool)
-
-	exportables, err := es.EnvExportableList(ctx, envName)
-	if err != nil {
-		if errors.Is(err, models.ErrEnvNotFound) && noEnvNoProblem {
-			return nil
-		}
-		return fmt.Errorf("could not list exportable env vars: %s: %w", envName, err)
-	}
-
-	if len(exportables) == 0 {
-		return nil
-	}
-
-	kvs := make([]kv, 0, len(exportables))
-	for _, e := range exportables {
-		// TODO: I don't like switching on the script type.
-		var include bool
-		switch scriptType {
-		case "export":
-			include = e.Enabled
-		case "unexport":
-			include = true
-		}
-		if include {
-			kvs = append(kvs, kv{
-				Name:  e.Name,
-				Value: e.Value,
-			})
-		}
-	}
-	if len(kvs) == 0 {
-		return nil
-	}
-	fmt.Fprintf(cmdCtx.Stdout, "printf '%s:';\n", cmdCtx.App.Name)
-
-	for _, e := range kvs {
-		switch scriptType {
-		case "export":
-			fmt.Fprintf(cmdCtx.Stdout, "printf ' +%s';\n", shellescape.Quote(e.Name))
-			fmt.Fprintf(cmdCtx.Stdout, "export %s=%s;\n", shellescape.Quote(e.Name), shellescape.Quote(e.Value))
-		case "unexport":
-			fmt.Fprintf(cmdCtx.Stdout, "printf ' -%s';\n", shellescape.Quote(e.Name))
-			fmt.Fprintf(cmdCtx.Stdout, "unset %s;\n", shellescape.Quote(e.Name))
-		default:
-			return errors.New("unimplemented --script-type: " + scriptType)
-		}
-	}
-	fmt.Fprintf(cmdCtx.Stdout, "echo;\n")
-	return nil
+func ShellZshUnexportCmd() warg.Cmd {
+	return shellUnexportCmd(posixDialect())
 }
 
 func ShellZshChdirCmd() warg.Cmd {
-	return warg.NewCmd(
-		"Change directory and corresponding env vars",
-		withSetup(shellZshChdirRun),
-		// TODO: maybe define the flags here to get better descriptions.
-		warg.CmdFlag("--old", envNameFlag()),
-		warg.CmdFlag("--new", envNameFlag()),
-		warg.CmdFlagMap(timeoutFlagMap()),
-		warg.CmdFlagMap(sqliteDSNFlagMap()),
-	)
-}
-
-type LookupEnvFunc = func(key string) (string, bool)
-type CustomLookupEnvFuncKey struct{}
-
-// LookupMap loooks up keys from a provided map. Useful to mock os.LookupEnv when parsing
-func LookupMap(m map[string]string) LookupEnvFunc {
-	return func(key string) (string, bool) {
-		val, exists := m[key]
-		return val, exists
-	}
+	return shellChdirCmd(posixDialect())
 }
 
-func shellZshChdirRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
-	oldEnvName := cmdCtx.Flags["--old"].(string)
-	newEnvName := cmdCtx.Flags["--new"].(string)
-
-	lookupEnv := os.LookupEnv
-	if custom, exists := cmdCtx.ParseMetadata.Get(CustomLookupEnvFuncKey{}); exists {
-		lookupEnv = custom.(LookupEnvFunc)
-	}
-
-	newExportables, err := es.EnvExportableList(ctx, newEnvName)
-	if err != nil && !errors.Is(err, models.ErrEnvNotFound) {
-		return fmt.Errorf("could not list new env exportables: %s: %w", newEnvName, err)
-	}
-	// we did successfully find an env - clear it out if its not enabled
-	if err == nil {
-		env, err := es.EnvShow(ctx, newEnvName)
-		if err != nil {
-			return fmt.Errorf("could not show new env: %s: %w", newEnvName, err)
-		}
-		if !env.Enabled {
-			newExportables = nil
-		}
-	}
-	oldExportables, err := es.EnvExportableList(ctx, oldEnvName)
-	if err != nil && !errors.Is(err, models.ErrEnvNotFound) {
-		return fmt.Errorf("could not list old env exportables: %s: %w", oldEnvName, err)
-	}
-
-	// TODO: figure out how envs and exportables being disabled should be handled here
-	newKVs := make(map[string]string, len(newExportables))
-	oldKVs := make(map[string]string, len(oldExportables))
-	for _, ev := range newExportables {
-		if ev.Enabled {
-			newKVs[ev.Name] = ev.Value
-		}
-	}
-	for _, ev := range oldExportables {
-		// if it exists in the new env, we don't need to process in the old env
-		// Let's also not consider enabled here, as these are slated to be removed anyway. So we want to unset them even if they are disabled in the old env, as long as they don't exist in the new env.
-		if _, exists := newKVs[ev.Name]; exists {
-			continue
-		}
-		oldKVs[ev.Name] = ev.Value
-	}
-
-	todo := computeExportChanges(oldKVs, newKVs, lookupEnv)
-
-	if len(todo.ToAdd)+len(todo.ToChange)+len(todo.ToRemove)+len(todo.Unchanged) == 0 {
-		return nil
-	}
-
-	fmt.Fprintf(cmdCtx.Stdout, "printf '%s:';\n", cmdCtx.App.Name)
-
-	// print the change script
-	for _, kv := range todo.ToAdd {
-		fmt.Fprintf(cmdCtx.Stdout, "printf ' +%s';\n", shellescape.Quote(kv.Name))
-		fmt.Fprintf(cmdCtx.Stdout, "export %s=%s;\n", shellescape.Quote(kv.Name), shellescape.Quote(kv.Value))
-	}
-	for _, kv := range todo.ToChange {
-		fmt.Fprintf(cmdCtx.Stdout, "printf ' ~%s';\n", shellescape.Quote(kv.Name))
-		fmt.Fprintf(cmdCtx.Stdout, "export %s=%s;\n", shellescape.Quote(kv.Name), shellescape.Quote(kv.Value))
-	}
-	for _, kv := range todo.ToRemove {
-		fmt.Fprintf(cmdCtx.Stdout, "printf ' -%s';\n", shellescape.Quote(kv.Name))
-		fmt.Fprintf(cmdCtx.Stdout, "unset %s;\n", shellescape.Quote(kv.Name))
-	}
-	for _, kv := range todo.Unchanged {
-		fmt.Fprintf(cmdCtx.Stdout, "printf ' =%s';\n", shellescape.Quote(kv.Name))
-	}
-
-	fmt.Fprint(cmdCtx.Stdout, "echo;\n")
-	return nil
+func ShellZshPushCmd() warg.Cmd {
+	return shellPushCmd(posixDialect())
 }
 
-type kv struct {
-	Name  string
-	Value string
+func ShellZshPopCmd() warg.Cmd {
+	return shellPopCmd(posixDialect())
 }
 
-type computeExportChangesResult struct {
-	ToAdd     []kv
-	ToChange  []kv
-	ToRemove  []kv
-	Unchanged []kv
+func ShellZshStackCmd() warg.Cmd {
+	return shellStackCmd()
 }
 
-func computeExportChanges(oldKVs, newKVs map[string]string, lookupFunc func(string) (string, bool)) computeExportChangesResult {
-	res := computeExportChangesResult{
-		ToAdd:     nil,
-		ToChange:  nil,
-		ToRemove:  nil,
-		Unchanged: nil,
-	}
-
-	for key, val := range oldKVs {
-		_, exists := lookupFunc(key)
-		if exists {
-			res.ToRemove = append(res.ToRemove, kv{Name: key, Value: val})
-		}
-	}
-
-	for key, val := range newKVs {
-		envVal, exists := lookupFunc(key)
-		if exists {
-			if envVal == val {
-				res.Unchanged = append(res.Unchanged, kv{Name: key, Value: val})
-			} else {
-				res.ToChange = append(res.ToChange, kv{Name: key, Value: val})
-			}
-		} else {
-			res.ToAdd = append(res.ToAdd, kv{Name: key, Value: val})
-		}
-	}
-	cmp := func(a, b kv) int {
-		return strings.Compare(a.Name, b.Name)
-	}
-	slices.SortFunc(res.ToAdd, cmp)
-	slices.SortFunc(res.ToChange, cmp)
-	slices.SortFunc(res.ToRemove, cmp)
-	slices.SortFunc(res.Unchanged, cmp)
-	return res
+func ShellZshReloadCmd() warg.Cmd {
+	return shellReloadCmd(posixDialect())
 }
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"go.bbkane.com/enventory/cli/tableprint"
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+
+	"go.bbkane.com/warg/value/scalar"
+)
+
+const statusCmdHelpLong = `Compare the current environment to the vars the envs for a directory (and
+any pushed envs) should export. Each var gets a status:
+
+ok          the shell has the database value
+missing     the shell doesn't have the var
+disabled    the var, ref, or env is disabled, so it isn't exported
+overridden  the shell value was changed after enventory exported it, or was
+            never exported by enventory
+stale       the shell has the value enventory exported, but the database
+            value changed since
+scoped      the var is only passed to its scoped commands, so the shell
+            shouldn't have it
+
+Examples:
+
+enventory status
+enventory status --mask false`
+
+func StatusCmd() warg.Cmd {
+	return warg.NewCmd(
+		"Compare the current environment to the envs for a directory",
+		withSetup(statusRun),
+		warg.CmdHelpLong(statusCmdHelpLong),
+		warg.NewCmdFlag(
+			"--dir",
+			"Directory whose envs should be exported",
+			scalar.String(
+				scalar.Default(cwd),
+			),
+			warg.Required(),
+		),
+		warg.CmdFlagMap(hierarchicalFlagMap()),
+		warg.CmdFlagMap(maskFlag()),
+		warg.CmdFlagMap(timeoutFlagMap()),
+		warg.CmdFlagMap(sqliteDSNFlagMap()),
+		warg.CmdFlagMap(widthFlag()),
+	)
+}
+
+func statusRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
+	pc := newPathCanonicalizer(cmdCtx)
+	dir := pc.Dir(cmdCtx.Flags["--dir"].(string))
+	hierarchical := cmdCtx.Flags["--hierarchical"].(bool)
+	lookupEnv := lookupEnvFromCtx(cmdCtx)
+
+	envNames, err := dirEnvNames(ctx, es, pc, dir, hierarchical)
+	if err != nil {
+		return err
+	}
+	resolved, err := resolveExportables(ctx, es, slices.Concat(envNames, readStack(lookupEnv)))
+	if err != nil {
+		return fmt.Errorf("could not resolve envs: %w", err)
+	}
+	// run command vars and expand templates like chdir would, reporting the
+	// ones that fail
+	unresolved := maps.Clone(resolved)
+	failed := resolveVarValues(ctx, es, cmdCtx, resolved)
+	for name := range failed {
+		resolved[name] = unresolved[name]
+	}
+
+	exported, _ := readExported(lookupEnv)
+	statuses := make([]tableprint.VarStatus, 0, len(resolved))
+	for _, name := range slices.Sorted(maps.Keys(resolved)) {
+		rv := resolved[name]
+		shellValue, shellExists := lookupEnv(name)
+		s := tableprint.VarStatus{
+			Name:        name,
+			Status:      varStatus(rv, shellValue, shellExists, exported[name]),
+			Source:      "var",
+			EnvName:     rv.Owner,
+			RefEnvName:  "",
+			Value:       rv.Value,
+			Scope:       rv.Scope,
+			ShellValue:  shellValue,
+			ShellExists: shellExists,
+			Error:       "",
+		}
+		if err, isFailed := failed[name]; isFailed {
+			s.Status = "failed"
+			s.Value = ""
+			s.Error = err.Error()
+		}
+		if rv.Type == "var_ref" {
+			varRef, _, err := es.VarRefShow(ctx, rv.Owner, name)
+			if err != nil {
+				return fmt.Errorf("could not show var ref: %s: %s: %w", rv.Owner, name, err)
+			}
+			s.Source = "ref"
+			s.RefEnvName = varRef.RefEnvName
+		}
+		statuses = append(statuses, s)
+	}
+
+	c := tableprint.CommonTablePrintArgs{
+		Format:          tableprint.Format_Table,
+		Mask:            mustGetMaskArg(cmdCtx.Flags),
+		Tz:              tableprint.Timezone_Local,
+		W:               cmdCtx.Stdout,
+		DesiredMaxWidth: mustGetWidthArg(cmdCtx.Flags),
+	}
+	tableprint.StatusPrint(c, statuses)
+	return nil
+}
+
+// varStatus compares rv to the shell's value for it. ev is what enventory
+// recorded exporting for it, and tells stale values from overridden ones.
+func varStatus(rv resolvedVar, shellValue string, shellExists bool, ev exportedVar) string {
+	switch {
+	case !rv.Enabled:
+		return "disabled"
+	case len(rv.Scope) > 0:
+		return "scoped"
+	case !shellExists:
+		return "missing"
+	case shellValue == rv.Value:
+		return "ok"
+	case ev.Fingerprint != "" && ev.Fingerprint == fingerprint(shellValue):
+		return "stale"
+	default:
+		return "overridden"
+	}
+}
@@ -0,0 +1,24 @@
+package tableprint
+
+import (
+	"fmt"
+
+	"go.bbkane.com/enventory/models"
+)
+
+func AliasShowPrint(c CommonTablePrintArgs, alias models.Alias) {
+	t := newKeyValueTable(c.W, c.DesiredMaxWidth)
+	createTime := formatTime(alias.CreateTime, c.Tz)
+	updateTime := formatTime(alias.UpdateTime, c.Tz)
+	t.Section(
+		newRow("EnvName", alias.EnvName),
+		newRow("Name", alias.Name),
+		newRow("Kind", string(alias.Kind)),
+		newRow("Value", alias.Value),
+		newRow("Comment", alias.Comment, skipRowIf(alias.Comment == "")),
+		newRow("CreateTime", createTime),
+		newRow("UpdateTime", updateTime, skipRowIf(alias.CreateTime.Equal(alias.UpdateTime))),
+		newRow("Enabled", fmt.Sprintf("%t", alias.Enabled), skipRowIf(alias.Enabled)),
+	)
+	t.Render()
+}
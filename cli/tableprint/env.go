@@ -2,23 +2,57 @@ package tableprint
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"go.bbkane.com/enventory/models"
 )
 
-func EnvList(c CommonTablePrintArgs, envs []models.Env) {
+// repoRows lists the git repos an env is activated in
+func repoRows(repos []string) []row {
+	rows := make([]row, 0, len(repos))
+	for _, r := range repos {
+		rows = append(rows, newRow("Repo", r))
+	}
+	return rows
+}
+
+// hookRows shows an env's hooks and whether its hooks and command vars are
+// trusted. trusted is nil if it has neither.
+func hookRows(env models.Env, trusted *bool) []row {
+	return []row{
+		newRow("OnEnter", env.OnEnter, skipRowIf(env.OnEnter == "")),
+		newRow("OnLeave", env.OnLeave, skipRowIf(env.OnLeave == "")),
+		newRow("Trusted", fmt.Sprintf("%t", trusted != nil && *trusted), skipRowIf(trusted == nil)),
+	}
+}
+
+// trustedPtr returns trusted[envName], or nil if it's not there
+func trustedPtr(trusted map[string]bool, envName string) *bool {
+	if t, exists := trusted[envName]; exists {
+		return &t
+	}
+	return nil
+}
+
+// EnvList prints envs. trusted reports whether each env's hooks and command
+// vars are trusted, by env name. Envs without either aren't in it.
+func EnvList(c CommonTablePrintArgs, envs []models.Env, trusted map[string]bool) {
 	if len(envs) > 0 {
 		t := newKeyValueTable(c.W, c.DesiredMaxWidth)
 		for _, e := range envs {
 			createTime := formatTime(e.CreateTime, c.Tz)
 			updateTime := formatTime(e.UpdateTime, c.Tz)
-			t.Section(
-				newRow("Name", e.Name),
-				newRow("Comment", e.Comment, skipRowIf(e.Comment == "")),
-				newRow("CreateTime", createTime),
-				newRow("UpdateTime", updateTime, skipRowIf(e.CreateTime.Equal(e.UpdateTime))),
-				newRow("Enabled", fmt.Sprintf("%t", e.Enabled), skipRowIf(e.Enabled)),
-			)
+			t.Section(append(
+				[]row{
+					newRow("Name", e.Name),
+					newRow("Comment", e.Comment, skipRowIf(e.Comment == "")),
+					newRow("CreateTime", createTime),
+					newRow("UpdateTime", updateTime, skipRowIf(e.CreateTime.Equal(e.UpdateTime))),
+					newRow("Enabled", fmt.Sprintf("%t", e.Enabled), skipRowIf(e.Enabled)),
+				},
+				slices.Concat(hookRows(e, trustedPtr(trusted, e.Name)), repoRows(e.Repos))...,
+			)...)
 		}
 		t.Render()
 	} else {
@@ -29,9 +63,15 @@ func EnvList(c CommonTablePrintArgs, envs []models.Env) {
 func EnvShowRun(
 	c CommonTablePrintArgs,
 	env models.Env,
+	trusted *bool,
 	localvars []models.Var,
 	refs []models.VarRef,
 	referencedVars []models.Var,
+	aliases []models.Alias,
+	includes []string,
+	inherited []models.EnvExportable,
+	paths []models.EnvPath,
+	patterns []models.EnvPattern,
 ) {
 	switch c.Format {
 	case Format_Table:
@@ -40,13 +80,16 @@ func EnvShowRun(
 		t := newKeyValueTable(c.W, c.DesiredMaxWidth)
 		createTime := formatTime(env.CreateTime, c.Tz)
 		updateTime := formatTime(env.UpdateTime, c.Tz)
-		t.Section(
-			newRow("Name", env.Name),
-			newRow("Comment", env.Comment, skipRowIf(env.Comment == "")),
-			newRow("CreateTime", createTime),
-			newRow("UpdateTime", updateTime, skipRowIf(env.CreateTime.Equal(env.UpdateTime))),
-			newRow("Enabled", fmt.Sprintf("%t", env.Enabled), skipRowIf(env.Enabled)),
-		)
+		t.Section(append(
+			[]row{
+				newRow("Name", env.Name),
+				newRow("Comment", env.Comment, skipRowIf(env.Comment == "")),
+				newRow("CreateTime", createTime),
+				newRow("UpdateTime", updateTime, skipRowIf(env.CreateTime.Equal(env.UpdateTime))),
+				newRow("Enabled", fmt.Sprintf("%t", env.Enabled), skipRowIf(env.Enabled)),
+			},
+			slices.Concat(hookRows(env, trusted), repoRows(env.Repos))...,
+		)...)
 		t.Render()
 
 		if len(localvars) > 0 {
@@ -58,8 +101,10 @@ func EnvShowRun(
 				t.Section(
 					newRow("Name", e.Name),
 					newRow("Value", mask(c.Mask, e.Value)),
+					newRow("Kind", string(e.Kind), skipRowIf(e.Kind != models.VarKindCommand)),
 					newRow("Comment", e.Comment, skipRowIf(e.Comment == "")),
 					newRow("Enabled", fmt.Sprintf("%t", e.Enabled), skipRowIf(e.Enabled)),
+					newRow("Scope", strings.Join(e.Scope, ","), skipRowIf(len(e.Scope) == 0)),
 				)
 			}
 			t.Render()
@@ -82,6 +127,69 @@ func EnvShowRun(
 			t.Render()
 
 		}
+
+		if len(aliases) > 0 {
+			fmt.Fprintln(c.W, "Aliases")
+			t := newKeyValueTable(c.W, c.DesiredMaxWidth)
+			for _, a := range aliases {
+				t.Section(
+					newRow("Name", a.Name),
+					newRow("Kind", string(a.Kind)),
+					newRow("Value", a.Value),
+					newRow("Comment", a.Comment, skipRowIf(a.Comment == "")),
+					newRow("Enabled", fmt.Sprintf("%t", a.Enabled), skipRowIf(a.Enabled)),
+				)
+			}
+			t.Render()
+		}
+
+		if len(paths) > 0 {
+			fmt.Fprintln(c.W, "Paths")
+			t := newKeyValueTable(c.W, c.DesiredMaxWidth)
+			for _, p := range paths {
+				t.Section(
+					newRow("Path", p.Path),
+				)
+			}
+			t.Render()
+		}
+
+		if len(patterns) > 0 {
+			fmt.Fprintln(c.W, "Patterns")
+			t := newKeyValueTable(c.W, c.DesiredMaxWidth)
+			for _, p := range patterns {
+				t.Section(
+					newRow("Pattern", p.Pattern),
+					newRow("Kind", string(p.Kind)),
+				)
+			}
+			t.Render()
+		}
+
+		if len(includes) > 0 {
+			fmt.Fprintln(c.W, "Includes")
+			t := newKeyValueTable(c.W, c.DesiredMaxWidth)
+			for _, include := range includes {
+				t.Section(
+					newRow("EnvName", include),
+				)
+			}
+			t.Render()
+		}
+
+		if len(inherited) > 0 {
+			fmt.Fprintln(c.W, "Inherited")
+			t := newKeyValueTable(c.W, c.DesiredMaxWidth)
+			for _, e := range inherited {
+				t.Section(
+					newRow("Name", e.Name),
+					newRow("Value", mask(c.Mask, e.Value)),
+					newRow("FromEnvName", e.EnvName),
+					newRow("Enabled", fmt.Sprintf("%t", e.Enabled), skipRowIf(e.Enabled)),
+				)
+			}
+			t.Render()
+		}
 	default:
 		panic("unexpected format: " + string(c.Format))
 	}
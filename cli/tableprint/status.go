@@ -0,0 +1,43 @@
+package tableprint
+
+import (
+	"strings"
+)
+
+// VarStatus compares a var that should be exported to the shell's value
+type VarStatus struct {
+	Name string
+	// Status is ok, missing, disabled, overridden, stale, scoped, or failed
+	Status string
+	// Source is var or ref
+	Source string
+	// EnvName owns the var or ref
+	EnvName string
+	// RefEnvName owns the var a ref points to
+	RefEnvName string
+	Value      string
+	Scope      []string
+	// ShellValue is the value in the shell, if ShellExists
+	ShellValue  string
+	ShellExists bool
+	// Error is why a failed command var's command failed
+	Error string
+}
+
+func StatusPrint(c CommonTablePrintArgs, statuses []VarStatus) {
+	t := newKeyValueTable(c.W, c.DesiredMaxWidth)
+	for _, s := range statuses {
+		t.Section(
+			newRow("Name", s.Name),
+			newRow("Status", s.Status),
+			newRow("Source", s.Source),
+			newRow("EnvName", s.EnvName),
+			newRow("RefEnvName", s.RefEnvName, skipRowIf(s.RefEnvName == "")),
+			newRow("Value", mask(c.Mask, s.Value)),
+			newRow("Scope", strings.Join(s.Scope, ","), skipRowIf(len(s.Scope) == 0)),
+			newRow("ShellValue", mask(c.Mask, s.ShellValue), skipRowIf(!s.ShellExists || s.ShellValue == s.Value)),
+			newRow("Error", s.Error, skipRowIf(s.Error == "")),
+		)
+	}
+	t.Render()
+}
@@ -2,28 +2,60 @@ package tableprint
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"go.bbkane.com/enventory/models"
 )
 
-func VarShowPrint(c CommonTablePrintArgs, envVar models.Var, envRefs []models.VarRef) {
+// commandRows shows a command var's command and its cached output. cache is
+// nil if the command hasn't run since it was last edited.
+func commandRows(c CommonTablePrintArgs, envVar models.Var, cache *models.VarCache, now time.Time) []row {
+	if envVar.Kind != models.VarKindCommand {
+		return []row{newRow("Value", mask(c.Mask, envVar.Value))}
+	}
+	rows := []row{
+		newRow("Kind", string(envVar.Kind)),
+		newRow("Command", envVar.Value),
+		newRow("CacheTTL", envVar.CacheTTL.String()),
+	}
+	if cache == nil {
+		return append(rows, newRow("CacheAge", "not cached"))
+	}
+	age := now.Sub(cache.CacheTime).Round(time.Second)
+	ageStr := age.String()
+	if age >= envVar.CacheTTL {
+		ageStr += " (expired)"
+	}
+	return append(rows,
+		newRow("Value", mask(c.Mask, cache.Value)),
+		newRow("CacheAge", ageStr),
+	)
+}
+
+func VarShowPrint(c CommonTablePrintArgs, envVar models.Var, envRefs []models.VarRef, cache *models.VarCache, now time.Time) {
 
 	switch c.Format {
 	case Format_Table:
 		t := newKeyValueTable(c.W, c.DesiredMaxWidth)
 		createTime := formatTime(envVar.CreateTime, c.Tz)
 		updateTime := formatTime(envVar.UpdateTime, c.Tz)
-		t.Section(
-			newRow("EnvName", envVar.EnvName),
-			newRow("Name", envVar.Name),
-			newRow("Value", mask(c.Mask, envVar.Value)),
-			newRow("Comment", envVar.Comment, skipRowIf(envVar.Comment == "")),
-			newRow("CreateTime", createTime),
-			newRow("UpdateTime", updateTime, skipRowIf(envVar.CreateTime.Equal(envVar.UpdateTime))),
-			newRow("Enabled", fmt.Sprintf("%t", envVar.Enabled), skipRowIf(envVar.Enabled)),
-			newRow("Completions", strings.Join(envVar.Completions, ","), skipRowIf(len(envVar.Completions) == 0)),
-		)
+		t.Section(slices.Concat(
+			[]row{
+				newRow("EnvName", envVar.EnvName),
+				newRow("Name", envVar.Name),
+			},
+			commandRows(c, envVar, cache, now),
+			[]row{
+				newRow("Comment", envVar.Comment, skipRowIf(envVar.Comment == "")),
+				newRow("CreateTime", createTime),
+				newRow("UpdateTime", updateTime, skipRowIf(envVar.CreateTime.Equal(envVar.UpdateTime))),
+				newRow("Enabled", fmt.Sprintf("%t", envVar.Enabled), skipRowIf(envVar.Enabled)),
+				newRow("Completions", strings.Join(envVar.Completions, ","), skipRowIf(len(envVar.Completions) == 0)),
+				newRow("Scope", strings.Join(envVar.Scope, ","), skipRowIf(len(envVar.Scope) == 0)),
+			},
+		)...)
 		t.Render()
 
 		if len(envRefs) > 0 {
@@ -41,7 +73,13 @@ func VarShowPrint(c CommonTablePrintArgs, envVar models.Var, envRefs []models.Va
 			t.Render()
 		}
 	case Format_ValueOnly:
-		fmt.Print(envVar.Value)
+		switch {
+		case envVar.Kind != models.VarKindCommand:
+			fmt.Print(envVar.Value)
+		case cache != nil:
+			// the command's last output
+			fmt.Print(cache.Value)
+		}
 	default:
 		panic("unexpected format: " + string(c.Format))
 	}
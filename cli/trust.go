@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+)
+
+// Env hooks and command vars run arbitrary code, so they only run once the
+// user trusts them. What the user trusted is kept in a directory outside the
+// database, like direnv's allow directory. Anyone who can write the database
+// (or sync it from another machine) can edit the code, but can't also mark it
+// trusted.
+
+// trustDirEnvVar overrides where trusted hashes are kept
+const trustDirEnvVar = "ENVENTORY_TRUST_DIR"
+
+// trustStore holds one file per trusted env and hash of its code (see
+// envCode)
+type trustStore struct {
+	// dir is "" if no directory could be found, so nothing is trusted
+	dir string
+}
+
+// newTrustStore uses $ENVENTORY_TRUST_DIR, $XDG_DATA_HOME/enventory/trust, or
+// ~/.local/share/enventory/trust
+func newTrustStore(cmdCtx warg.CmdContext) trustStore {
+	lookupEnv := lookupEnvFromCtx(cmdCtx)
+	if dir, exists := lookupEnv(trustDirEnvVar); exists && dir != "" {
+		return trustStore{dir: dir}
+	}
+	if dataHome, exists := lookupEnv("XDG_DATA_HOME"); exists && filepath.IsAbs(dataHome) {
+		return trustStore{dir: filepath.Join(dataHome, "enventory", "trust")}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return trustStore{dir: ""}
+	}
+	return trustStore{dir: filepath.Join(home, ".local", "share", "enventory", "trust")}
+}
+
+// path names the file recording that envName's content with hash is trusted.
+// Renaming the env or changing the content changes it.
+func (ts trustStore) path(envName string, hash string) string {
+	sum := sha256.Sum256([]byte(envName + "\x00" + hash))
+	return filepath.Join(ts.dir, hex.EncodeToString(sum[:]))
+}
+
+// Trusted reports whether Trust was called with envName and hash
+func (ts trustStore) Trusted(envName string, hash string) (bool, error) {
+	if ts.dir == "" {
+		return false, nil
+	}
+	_, err := os.Stat(ts.path(envName, hash))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not check trust: %s: %w", envName, err)
+	}
+	return true, nil
+}
+
+// Trust records that envName's content with hash is trusted
+func (ts trustStore) Trust(envName string, hash string) error {
+	if ts.dir == "" {
+		return fmt.Errorf("could not find a trust directory: set %s", trustDirEnvVar)
+	}
+	if err := os.MkdirAll(ts.dir, 0o700); err != nil {
+		return fmt.Errorf("could not create trust directory: %w", err)
+	}
+	// the env name is only there for people looking through the directory
+	if err := os.WriteFile(ts.path(envName, hash), []byte(envName+"\n"), 0o600); err != nil {
+		return fmt.Errorf("could not trust: %s: %w", envName, err)
+	}
+	return nil
+}
+
+// envCode is what trusting an env covers: its hooks and the commands of its
+// own command vars
+type envCode struct {
+	Env models.Env
+	// Commands maps command var names to their commands
+	Commands map[string]string
+}
+
+// loadEnvCode returns the code env runs
+func loadEnvCode(ctx context.Context, es models.Service, env models.Env) (envCode, error) {
+	vars, err := es.VarList(ctx, env.Name)
+	if err != nil {
+		return envCode{}, fmt.Errorf("could not list vars: %s: %w", env.Name, err)
+	}
+	commands := map[string]string{}
+	for _, v := range vars {
+		if v.Kind == models.VarKindCommand {
+			commands[v.Name] = v.Value
+		}
+	}
+	return envCode{Env: env, Commands: commands}, nil
+}
+
+// Empty reports whether there's nothing to trust
+func (c envCode) Empty() bool {
+	return !c.Env.HasHooks() && len(c.Commands) == 0
+}
+
+// Hash identifies the code, so editing any of it needs trusting it again
+func (c envCode) Hash() string {
+	return models.TrustHash(c.Env.OnEnter, c.Env.OnLeave, c.Commands)
+}
+
+// envTrusted reports whether env's hooks and command vars were trusted as
+// they are now. It returns nil if env has neither.
+func envTrusted(ctx context.Context, es models.Service, ts trustStore, env models.Env) (*bool, error) {
+	code, err := loadEnvCode(ctx, es, env)
+	if err != nil {
+		return nil, err
+	}
+	if code.Empty() {
+		return nil, nil
+	}
+	trusted, err := ts.Trusted(env.Name, code.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return &trusted, nil
+}
+
+// commandTrust returns a func reporting whether an env's command vars may
+// run. It remembers the answer for each env.
+func commandTrust(ctx context.Context, es models.Service, ts trustStore) func(envName string) (bool, error) {
+	cache := map[string]bool{}
+	return func(envName string) (bool, error) {
+		if trusted, exists := cache[envName]; exists {
+			return trusted, nil
+		}
+		env, err := es.EnvShow(ctx, envName)
+		if err != nil {
+			return false, fmt.Errorf("could not show env: %s: %w", envName, err)
+		}
+		trusted, err := envTrusted(ctx, es, ts, *env)
+		if err != nil {
+			return false, err
+		}
+		cache[envName] = trusted != nil && *trusted
+		return cache[envName], nil
+	}
+}
@@ -3,8 +3,10 @@ package cli
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"go.bbkane.com/enventory/cli/tableprint"
 	"go.bbkane.com/enventory/models"
@@ -40,6 +42,28 @@ func VarCreateCmd() warg.Cmd {
 			"Comma-separated list of tab completions for this var's value to easily toggle between known values.",
 			scalar.String(),
 		),
+		warg.NewCmdFlag(
+			"--scope",
+			"Comma-separated list of commands (e.g. gh,aws) this var is passed to instead of being exported into the shell",
+			scalar.String(),
+		),
+		warg.NewCmdFlag(
+			"--kind",
+			"value exports --value, expanding ${NAME} with other exported vars and ${env:NAME} with the shell's env vars. command runs --value with sh when exporting and exports its output",
+			scalar.String(
+				scalar.Choices(string(models.VarKindValue), string(models.VarKindCommand)),
+				scalar.Default(string(models.VarKindValue)),
+			),
+			warg.Required(),
+		),
+		warg.NewCmdFlag(
+			"--cache-ttl",
+			"How long a command var reuses its command's output. Use https://pkg.go.dev/time#Duration to build it",
+			scalar.Duration(
+				scalar.Default(time.Hour),
+			),
+			warg.Required(),
+		),
 	)
 }
 
@@ -48,7 +72,7 @@ func varCreateRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext
 	// common create Flags
 	commonCreateArgs := mustGetCommonCreateArgs(cmdCtx.Flags)
 
-	envName := mustGetEnvNameArg(cmdCtx.Flags)
+	envName := envNameArg(cmdCtx, "--env")
 	value, exists := cmdCtx.Flags["--value"].(string)
 	if !exists {
 		fmt.Print("Enter value: ")
@@ -63,6 +87,15 @@ func varCreateRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext
 	name := mustGetNameArg(cmdCtx.Flags)
 
 	completions := parseCompletions(cmdCtx.Flags, "--completions")
+	scope := parseCompletions(cmdCtx.Flags, "--scope")
+	if err := validateScope(scope); err != nil {
+		return err
+	}
+	kind := models.VarKind(cmdCtx.Flags["--kind"].(string))
+	cacheTTL := cmdCtx.Flags["--cache-ttl"].(time.Duration)
+	if err := validateVarTemplate(kind, value); err != nil {
+		return err
+	}
 
 	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
 		_, err := es.VarCreate(
@@ -76,6 +109,9 @@ func varCreateRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext
 				Value:       value,
 				Enabled:     commonCreateArgs.Enabled,
 				Completions: completions,
+				Scope:       scope,
+				Kind:        kind,
+				CacheTTL:    cacheTTL,
 			},
 		)
 		if err != nil {
@@ -107,7 +143,7 @@ func VarDeleteCmd() warg.Cmd {
 }
 
 func varDeleteRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
-	envName := mustGetEnvNameArg(cmdCtx.Flags)
+	envName := envNameArg(cmdCtx, "--env")
 	name := mustGetNameArg(cmdCtx.Flags)
 
 	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
@@ -134,6 +170,16 @@ func VarShowCmd() warg.Cmd {
 		warg.CmdFlagMap(timeZoneFlagMap()),
 		warg.CmdFlagMap(formatFlag()),
 		warg.CmdFlagMap(widthFlag()),
+		warg.CmdFlagMap(shellNowFlagMap()),
+		warg.NewCmdFlag(
+			"--raw",
+			"Show the value as stored instead of expanding its ${NAME} and ${env:NAME} placeholders",
+			scalar.Bool(
+				scalar.Default(false),
+			),
+			warg.FlagGroup(flagGroupDisplay),
+			warg.Required(),
+		),
 		warg.CmdFlag("--name", varNameFlag()),
 		warg.CmdFlag(
 			"--env",
@@ -145,26 +191,49 @@ func VarShowCmd() warg.Cmd {
 func varShowRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
 
 	mask := mustGetMaskArg(cmdCtx.Flags)
-	envName := mustGetEnvNameArg(cmdCtx.Flags)
+	envName := envNameArg(cmdCtx, "--env")
 	name := mustGetNameArg(cmdCtx.Flags)
 	timezone := mustGetTimezoneArg(cmdCtx.Flags)
 	format := cmdCtx.Flags["--format"].(string)
 	width := mustGetWidthArg(cmdCtx.Flags)
+	raw := cmdCtx.Flags["--raw"].(bool)
 
 	var envVar *models.Var
 	var envRefs []models.VarRef
+	var cache *models.VarCache
 	err := es.WithTx(ctx, func(ctxt context.Context, es models.Service) error {
 		var err error
 		envVar, envRefs, err = es.VarShow(ctx, envName, name)
 		if err != nil {
 			return fmt.Errorf("couldn't find env var: %s: %w", name, err)
 		}
+		if envVar.Kind == models.VarKindCommand {
+			cache, err = es.VarCacheShow(ctx, envName, name)
+			if errors.Is(err, models.ErrVarCacheNotFound) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("couldn't find var cache: %s: %w", name, err)
+			}
+			// the output of an edited command doesn't apply
+			if cache.Command != envVar.Value {
+				cache = nil
+			}
+		}
 		return nil
 	})
 	if err != nil {
 		return err
 	}
 
+	if !raw && envVar.Kind == models.VarKindValue {
+		value, err := expandedVarValue(ctx, es, cmdCtx, *envVar)
+		if err != nil {
+			return err
+		}
+		envVar.Value = value
+	}
+
 	c := tableprint.CommonTablePrintArgs{
 		Format:          tableprint.Format(format),
 		Mask:            mask,
@@ -173,7 +242,7 @@ func varShowRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext)
 		DesiredMaxWidth: width,
 	}
 
-	tableprint.VarShowPrint(c, *envVar, envRefs)
+	tableprint.VarShowPrint(c, *envVar, envRefs, cache, nowArg(cmdCtx))
 	return nil
 }
 
@@ -238,6 +307,23 @@ func VarUpdateCmd() warg.Cmd {
 			"Comma-separated list of completions for this var",
 			scalar.String(),
 		),
+		warg.NewCmdFlag(
+			"--scope",
+			"Comma-separated list of commands this var is passed to instead of being exported. Pass '' to export it normally",
+			scalar.String(),
+		),
+		warg.NewCmdFlag(
+			"--kind",
+			"New kind",
+			scalar.String(
+				scalar.Choices(string(models.VarKindValue), string(models.VarKindCommand)),
+			),
+		),
+		warg.NewCmdFlag(
+			"--cache-ttl",
+			"New cache TTL for a command var",
+			scalar.Duration(),
+		),
 	)
 }
 
@@ -245,13 +331,41 @@ func varUpdateRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext
 	// common update flags
 	commonUpdateArgs := getCommonUpdateArgs(cmdCtx.Flags)
 
-	envName := mustGetEnvNameArg(cmdCtx.Flags)
+	envName := envNameArg(cmdCtx, "--env")
 	name := mustGetNameArg(cmdCtx.Flags)
-	newEnvName := ptrFromMap[string](cmdCtx.Flags, "--new-env")
+	newEnvName := envNameArgPtr(cmdCtx, "--new-env")
 	value := ptrFromMap[string](cmdCtx.Flags, "--value")
 	completions := parseCompletionsPtr(cmdCtx.Flags, "--completions")
+	scope := parseCompletionsPtr(cmdCtx.Flags, "--scope")
+	if scope != nil {
+		if err := validateScope(*scope); err != nil {
+			return err
+		}
+	}
+	var kind *models.VarKind
+	if k, exists := cmdCtx.Flags["--kind"]; exists {
+		tmp := models.VarKind(k.(string))
+		kind = &tmp
+	}
+	cacheTTL := ptrFromMap[time.Duration](cmdCtx.Flags, "--cache-ttl")
 
 	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
+		if value != nil || kind != nil {
+			existing, _, err := es.VarShow(ctx, envName, name)
+			if err != nil {
+				return fmt.Errorf("couldn't find env var: %s: %w", name, err)
+			}
+			newKind, newValue := existing.Kind, existing.Value
+			if kind != nil {
+				newKind = *kind
+			}
+			if value != nil {
+				newValue = *value
+			}
+			if err := validateVarTemplate(newKind, newValue); err != nil {
+				return err
+			}
+		}
 		err := es.VarUpdate(ctx, envName, name, models.VarUpdateArgs{
 			Comment:     commonUpdateArgs.Comment,
 			CreateTime:  commonUpdateArgs.CreateTime,
@@ -261,6 +375,9 @@ func varUpdateRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext
 			Value:       value,
 			Enabled:     commonUpdateArgs.Enabled,
 			Completions: completions,
+			Scope:       scope,
+			Kind:        kind,
+			CacheTTL:    cacheTTL,
 		})
 		if err != nil {
 			return fmt.Errorf("could not update env var: %w", err)
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+)
+
+// Command vars (var create --kind command) store a shell command as their
+// value. The command's output is what's exported, and it's cached in the
+// database for the var's CacheTTL so changing directories stays fast.
+
+// nowArg returns --now if the command has it, and the current time otherwise
+func nowArg(cmdCtx warg.CmdContext) time.Time {
+	if now, exists := cmdCtx.Flags["--now"]; exists {
+		return now.(time.Time)
+	}
+	return time.Now()
+}
+
+// cachedCommandOutput returns the cached output of e's command. It returns
+// false if there's no cache or the command was edited since. The cache might
+// be older than e.CacheTTL.
+func cachedCommandOutput(ctx context.Context, es models.Service, e models.EnvExportable) (*models.VarCache, bool, error) {
+	cache, err := es.VarCacheShow(ctx, e.VarEnvName, e.VarName)
+	if errors.Is(err, models.ErrVarCacheNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("could not show var cache: %s: %s: %w", e.VarEnvName, e.VarName, err)
+	}
+	if cache.Command != e.Value {
+		return nil, false, nil
+	}
+	return cache, true, nil
+}
+
+// commandOutput returns the output of e's command, reusing the cached output
+// if it's younger than e.CacheTTL and caching it otherwise
+func commandOutput(ctx context.Context, es models.Service, e models.EnvExportable, now time.Time) (string, error) {
+	cache, cached, err := cachedCommandOutput(ctx, es, e)
+	if err != nil {
+		return "", err
+	}
+	if cached && now.Sub(cache.CacheTime) < e.CacheTTL {
+		return cache.Value, nil
+	}
+
+	output, err := runVarCommand(ctx, e.Value)
+	if err != nil {
+		return "", err
+	}
+	err = es.VarCacheSet(ctx, e.VarEnvName, e.VarName, models.VarCache{
+		Command:   e.Value,
+		Value:     output,
+		CacheTime: now,
+	})
+	if err != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+// runVarCommand runs command with sh and returns its output without trailing
+// newlines
+func runVarCommand(ctx context.Context, command string) (string, error) {
+	output, err := exec.CommandContext(ctx, "sh", "-c", command).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
+
+// resolveCommandExportables replaces the values of the enabled command
+// exportables with their commands' output. Exportables whose commands fail
+// or whose envs aren't trusted (see env trust) are removed, and their errors
+// are returned by name.
+func resolveCommandExportables(ctx context.Context, es models.Service, cmdCtx warg.CmdContext, exportables []models.EnvExportable) ([]models.EnvExportable, map[string]error) {
+	now := nowArg(cmdCtx)
+	trusted := commandTrust(ctx, es, newTrustStore(cmdCtx))
+	resolved := make([]models.EnvExportable, 0, len(exportables))
+	failed := map[string]error{}
+	for _, e := range exportables {
+		if e.Kind == models.VarKindCommand && e.Enabled {
+			isTrusted, err := trusted(e.VarEnvName)
+			if err != nil {
+				failed[e.Name] = err
+				continue
+			}
+			if !isTrusted {
+				failed[e.Name] = fmt.Errorf(
+					"skipped untrusted command var from %s. Review it, then run: %s env trust --name %s",
+					e.VarEnvName, cmdCtx.App.Name, e.VarEnvName,
+				)
+				continue
+			}
+			output, err := commandOutput(ctx, es, e, now)
+			if err != nil {
+				failed[e.Name] = fmt.Errorf("could not run command: %w", err)
+				continue
+			}
+			e.Value = output
+		}
+		resolved = append(resolved, e)
+	}
+	return resolved, failed
+}
@@ -49,10 +49,10 @@ func varRefCreateRun(ctx context.Context, es models.Service, cmdCtx warg.CmdCont
 	commonCreateArgs := mustGetCommonCreateArgs(cmdCtx.Flags)
 
 	name := mustGetNameArg(cmdCtx.Flags)
-	refEnvName := cmdCtx.Flags["--ref-env"].(string)
+	refEnvName := envNameArg(cmdCtx, "--ref-env")
 	refVarName := cmdCtx.Flags["--ref-var"].(string)
 
-	envName := mustGetEnvNameArg(cmdCtx.Flags)
+	envName := envNameArg(cmdCtx, "--env")
 
 	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
 		_, err := es.VarRefCreate(
@@ -97,7 +97,7 @@ func VarRefDeleteCmd() warg.Cmd {
 }
 
 func varRefDeleteRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
-	envName := mustGetEnvNameArg(cmdCtx.Flags)
+	envName := envNameArg(cmdCtx, "--env")
 
 	name := mustGetNameArg(cmdCtx.Flags)
 
@@ -135,7 +135,7 @@ func VarRefShowCmd() warg.Cmd {
 }
 
 func varRefShowRun(ctx context.Context, es models.Service, cmdCtx warg.CmdContext) error {
-	envName := mustGetEnvNameArg(cmdCtx.Flags)
+	envName := envNameArg(cmdCtx, "--env")
 	mask := mustGetMaskArg(cmdCtx.Flags)
 	name := mustGetNameArg(cmdCtx.Flags)
 	timezone := mustGetTimezoneArg(cmdCtx.Flags)
@@ -202,10 +202,10 @@ func varRefUpdateRun(ctx context.Context, es models.Service, cmdCtx warg.CmdCont
 	// common update flags
 	commonUpdateArgs := getCommonUpdateArgs(cmdCtx.Flags)
 
-	envName := mustGetEnvNameArg(cmdCtx.Flags)
+	envName := envNameArg(cmdCtx, "--env")
 	name := mustGetNameArg(cmdCtx.Flags)
-	newEnvName := ptrFromMap[string](cmdCtx.Flags, "--new-env")
-	refEnvName := ptrFromMap[string](cmdCtx.Flags, "--ref-env")
+	newEnvName := envNameArgPtr(cmdCtx, "--new-env")
+	refEnvName := envNameArgPtr(cmdCtx, "--ref-env")
 	refVarName := ptrFromMap[string](cmdCtx.Flags, "--ref-var")
 
 	err := es.WithTx(ctx, func(ctx context.Context, es models.Service) error {
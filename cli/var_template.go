@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"go.bbkane.com/enventory/models"
+	"go.bbkane.com/warg"
+)
+
+// Var values are templates referencing the other vars exported with them
+// (see models.ExpandTemplate). They're expanded after command vars run, so
+// templates can use command output.
+
+// validateVarTemplate checks the placeholders in the value of a var of kind
+func validateVarTemplate(kind models.VarKind, value string) error {
+	if kind == models.VarKindCommand {
+		return nil
+	}
+	if err := models.ValidateTemplate(value); err != nil {
+		return fmt.Errorf("value must be a valid template (write $${ for a literal ${): %w", err)
+	}
+	return nil
+}
+
+// expandTemplates expands the values in values that isTemplate reports as
+// templates, expanding the values they reference first. Values that can't be
+// expanded are removed and their errors added to failed, which also holds
+// the names already removed.
+func expandTemplates(values map[string]string, isTemplate func(name string) bool, failed map[string]error, lookupEnv LookupEnvFunc) {
+	done := map[string]bool{}
+	// path is the chain of references leading to its last name
+	var expand func(path []string) error
+	expand = func(path []string) error {
+		name := path[len(path)-1]
+		if done[name] {
+			return nil
+		}
+		if slices.Contains(path[:len(path)-1], name) {
+			return fmt.Errorf("%w: %s", models.ErrVarReferenceCycle, strings.Join(path, " -> "))
+		}
+		if _, isFailed := failed[name]; isFailed {
+			return fmt.Errorf("references failed var: %s", strings.Join(path, " -> "))
+		}
+		value, exists := values[name]
+		if !exists {
+			return fmt.Errorf("%w: %s", models.ErrVarReferenceUnknown, strings.Join(path, " -> "))
+		}
+		if isTemplate(name) {
+			refs, err := models.TemplateReferences(value)
+			if err != nil {
+				return err
+			}
+			for _, ref := range refs {
+				if err := expand(append(slices.Clone(path), ref)); err != nil {
+					return err
+				}
+			}
+			expanded, err := models.ExpandTemplate(
+				value,
+				func(ref string) (string, bool) {
+					v, exists := values[ref]
+					return v, exists
+				},
+				lookupEnv,
+			)
+			if err != nil {
+				return err
+			}
+			values[name] = expanded
+		}
+		done[name] = true
+		return nil
+	}
+
+	newlyFailed := map[string]error{}
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if err := expand([]string{name}); err != nil {
+			newlyFailed[name] = fmt.Errorf("could not expand template: %w", err)
+		}
+	}
+	for name, err := range newlyFailed {
+		delete(values, name)
+		failed[name] = err
+	}
+}
+
+// interpolateExportables expands the templates in exportables. Exportables
+// that can't be expanded are removed and their errors added to failed.
+// Failures of disabled exportables are ignored, since they aren't exported.
+func interpolateExportables(exportables []models.EnvExportable, failed map[string]error, lookupEnv LookupEnvFunc) []models.EnvExportable {
+	values := make(map[string]string, len(exportables))
+	byName := make(map[string]models.EnvExportable, len(exportables))
+	for _, e := range exportables {
+		values[e.Name] = e.Value
+		byName[e.Name] = e
+	}
+	templateFailed := maps.Clone(failed)
+	expandTemplates(values, func(name string) bool { return byName[name].IsTemplate() }, templateFailed, lookupEnv)
+
+	interpolated := make([]models.EnvExportable, 0, len(exportables))
+	for _, e := range exportables {
+		value, expanded := values[e.Name]
+		switch {
+		case expanded:
+			e.Value = value
+		case e.Enabled:
+			failed[e.Name] = templateFailed[e.Name]
+			continue
+		}
+		interpolated = append(interpolated, e)
+	}
+	return interpolated
+}
+
+// resolveExportableValues runs the command vars in exportables, then expands
+// templates. Exportables that fail are removed, and their errors are returned
+// by name.
+func resolveExportableValues(ctx context.Context, es models.Service, cmdCtx warg.CmdContext, exportables []models.EnvExportable) ([]models.EnvExportable, map[string]error) {
+	exportables, failed := resolveCommandExportables(ctx, es, cmdCtx, exportables)
+	return interpolateExportables(exportables, failed, lookupEnvFromCtx(cmdCtx)), failed
+}
+
+// resolveVarValues is resolveExportableValues for merged vars. Vars that fail
+// are removed, and their errors are returned by name.
+func resolveVarValues(ctx context.Context, es models.Service, cmdCtx warg.CmdContext, resolved map[string]resolvedVar) map[string]error {
+	exportables := make([]models.EnvExportable, 0, len(resolved))
+	for _, name := range slices.Sorted(maps.Keys(resolved)) {
+		e := resolved[name].Exportable
+		e.Name = name
+		e.Value = resolved[name].Value
+		e.Enabled = resolved[name].Enabled
+		exportables = append(exportables, e)
+	}
+	exportables, failed := resolveExportableValues(ctx, es, cmdCtx, exportables)
+
+	values := make(map[string]string, len(exportables))
+	for _, e := range exportables {
+		values[e.Name] = e.Value
+	}
+	for name, rv := range resolved {
+		value, exists := values[name]
+		if !exists {
+			delete(resolved, name)
+			continue
+		}
+		rv.Value = value
+		resolved[name] = rv
+	}
+	return failed
+}
+
+// warnFailedVars prints why vars couldn't be resolved. The vars aren't
+// exported, but the rest are.
+func warnFailedVars(cmdCtx warg.CmdContext, failed map[string]error) {
+	for _, name := range slices.Sorted(maps.Keys(failed)) {
+		fmt.Fprintf(cmdCtx.Stderr, "%s: could not resolve %s: %v\n", cmdCtx.App.Name, name, failed[name])
+	}
+}
+
+// expandedVarValue returns v's value as its env would export it, running the
+// env's command vars if the value references them
+func expandedVarValue(ctx context.Context, es models.Service, cmdCtx warg.CmdContext, v models.Var) (string, error) {
+	if !strings.Contains(v.Value, "${") {
+		return v.Value, nil
+	}
+	exportables, err := es.EnvExportableList(ctx, v.EnvName)
+	if err != nil {
+		return "", fmt.Errorf("could not list exportable env vars: %s: %w", v.EnvName, err)
+	}
+	exportables, failed := resolveExportableValues(ctx, es, cmdCtx, exportables)
+	if err, isFailed := failed[v.Name]; isFailed {
+		return "", fmt.Errorf("could not resolve %s (see the template with --raw): %w", v.Name, err)
+	}
+	for _, e := range exportables {
+		if e.Name == v.Name && e.Type == "var" {
+			return e.Value, nil
+		}
+	}
+	// an include can't override the env's own var, so this is unreachable
+	return v.Value, nil
+}
@@ -0,0 +1,15 @@
+-- An env includes the vars and refs of other envs. Includes are resolved in
+-- position order, with later includes and the env's own vars taking precedence
+CREATE TABLE env_include (
+    env_include_id INTEGER PRIMARY KEY,
+    env_id INTEGER NOT NULL,
+    include_env_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    FOREIGN KEY (env_id) REFERENCES env(env_id) ON DELETE CASCADE,
+    FOREIGN KEY (include_env_id) REFERENCES env(env_id) ON DELETE RESTRICT,
+    UNIQUE(env_id, include_env_id),
+    CHECK(env_id != include_env_id)
+) STRICT;
+
+CREATE INDEX ix_env_include_env_id ON env_include(env_id);
+CREATE INDEX ix_env_include_include_env_id ON env_include(include_env_id);
@@ -0,0 +1,11 @@
+-- Path patterns an env is activated for in addition to its exact name
+CREATE TABLE env_pattern (
+    env_pattern_id INTEGER PRIMARY KEY,
+    env_id INTEGER NOT NULL,
+    pattern TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK(kind IN ('glob', 'regex')),
+    FOREIGN KEY (env_id) REFERENCES env(env_id) ON DELETE CASCADE,
+    UNIQUE(env_id, pattern)
+) STRICT;
+
+CREATE INDEX ix_env_pattern_env_id ON env_pattern(env_id);
@@ -0,0 +1,10 @@
+-- Directories an env is activated for in addition to the one it's named after
+CREATE TABLE env_path (
+    env_path_id INTEGER PRIMARY KEY,
+    env_id INTEGER NOT NULL,
+    path TEXT NOT NULL,
+    FOREIGN KEY (env_id) REFERENCES env(env_id) ON DELETE CASCADE,
+    UNIQUE(path)
+) STRICT;
+
+CREATE INDEX ix_env_path_env_id ON env_path(env_id);
@@ -0,0 +1,11 @@
+-- Git repos an env is activated in, keyed by their normalized origin remote
+-- URL so every clone and worktree of the repo gets the env
+CREATE TABLE env_repo (
+    env_repo_id INTEGER PRIMARY KEY,
+    env_id INTEGER NOT NULL,
+    repo TEXT NOT NULL,
+    FOREIGN KEY (env_id) REFERENCES env(env_id) ON DELETE CASCADE,
+    UNIQUE(repo)
+) STRICT;
+
+CREATE INDEX ix_env_repo_env_id ON env_repo(env_id);
@@ -0,0 +1,52 @@
+-- Commands a var is passed to instead of being exported into the shell. An
+-- empty list means the var is exported normally.
+ALTER TABLE var ADD COLUMN scope TEXT NOT NULL DEFAULT '[]';
+
+-- Drop and recreate vw_var_expanded to include scope
+DROP VIEW vw_var_expanded;
+CREATE VIEW vw_var_expanded AS
+SELECT
+    var_id,
+    env_id,
+    (SELECT name FROM env WHERE env_id = var.env_id) AS env_name,
+    name,
+    value,
+    comment,
+    create_time,
+    update_time,
+    enabled,
+    completions,
+    scope
+FROM var;
+
+-- Drop and recreate vw_env_exportable to include scope. Refs use the scope of
+-- the var they point to.
+DROP VIEW vw_env_exportable;
+CREATE VIEW vw_env_exportable AS
+SELECT
+    v.env_id,
+    (SELECT name FROM env WHERE env_id = v.env_id) AS env_name,
+    v.name,
+    'var' AS type,
+    v.comment,
+    v.enabled,
+    v.value,
+    v.create_time,
+    v.update_time,
+    v.scope
+FROM var v
+
+UNION ALL
+
+SELECT
+    vr.env_id,
+    (SELECT name FROM env WHERE env_id = vr.env_id) AS env_name,
+    vr.name,
+    'var_ref' AS type,
+    vr.comment,
+    vr.enabled,
+    (SELECT value FROM var WHERE var_id = vr.var_id) AS value,
+    vr.create_time,
+    vr.update_time,
+    (SELECT scope FROM var WHERE var_id = vr.var_id) AS scope
+FROM var_ref vr;
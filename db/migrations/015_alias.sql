@@ -0,0 +1,18 @@
+-- Shell aliases and functions an env defines while it's active. kind is
+-- 'alias' or 'function', and value is the alias's expansion or the function's
+-- body.
+CREATE TABLE alias (
+    alias_id INTEGER PRIMARY KEY,
+    env_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'alias',
+    value TEXT NOT NULL,
+    comment TEXT NOT NULL,
+    create_time TEXT NOT NULL,
+    update_time TEXT NOT NULL,
+    enabled INTEGER NOT NULL DEFAULT 1,
+    FOREIGN KEY (env_id) REFERENCES env(env_id) ON DELETE CASCADE,
+    UNIQUE(env_id, name)
+) STRICT;
+
+CREATE INDEX ix_alias_env_id ON alias(env_id);
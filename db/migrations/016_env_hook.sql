@@ -0,0 +1,3 @@
+-- Commands an env runs when the shell enters or leaves it
+ALTER TABLE env ADD COLUMN on_enter TEXT NOT NULL DEFAULT '';
+ALTER TABLE env ADD COLUMN on_leave TEXT NOT NULL DEFAULT '';
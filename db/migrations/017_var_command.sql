@@ -0,0 +1,57 @@
+-- Command vars store a shell command in value and export its output instead.
+-- The output is cached in var_cache for cache_ttl seconds so changing
+-- directories doesn't run the command every time.
+ALTER TABLE var ADD COLUMN kind TEXT NOT NULL DEFAULT 'value' CHECK(kind IN ('value', 'command'));
+ALTER TABLE var ADD COLUMN cache_ttl INTEGER NOT NULL DEFAULT 0;
+
+-- command is the command that produced value, so editing the command
+-- invalidates the cache
+CREATE TABLE var_cache (
+    var_id INTEGER PRIMARY KEY,
+    command TEXT NOT NULL,
+    value TEXT NOT NULL,
+    cache_time TEXT NOT NULL,
+    FOREIGN KEY (var_id) REFERENCES var(var_id) ON DELETE CASCADE
+) STRICT;
+
+-- Drop and recreate vw_env_exportable to include kind and cache_ttl, and the
+-- var providing the value so its cache can be found. Refs use the var they
+-- point to.
+DROP VIEW vw_env_exportable;
+CREATE VIEW vw_env_exportable AS
+SELECT
+    v.env_id,
+    (SELECT name FROM env WHERE env_id = v.env_id) AS env_name,
+    v.name,
+    'var' AS type,
+    v.comment,
+    v.enabled,
+    v.value,
+    v.create_time,
+    v.update_time,
+    v.scope,
+    v.kind,
+    v.cache_ttl,
+    (SELECT name FROM env WHERE env_id = v.env_id) AS var_env_name,
+    v.name AS var_name
+FROM var v
+
+UNION ALL
+
+SELECT
+    vr.env_id,
+    (SELECT name FROM env WHERE env_id = vr.env_id) AS env_name,
+    vr.name,
+    'var_ref' AS type,
+    vr.comment,
+    vr.enabled,
+    v.value,
+    vr.create_time,
+    vr.update_time,
+    v.scope,
+    v.kind,
+    v.cache_ttl,
+    (SELECT name FROM env WHERE env_id = v.env_id) AS var_env_name,
+    v.name AS var_name
+FROM var_ref vr
+JOIN var v ON v.var_id = vr.var_id;
@@ -0,0 +1,4 @@
+-- Var values became templates, where ${NAME} is replaced by another var's
+-- value. Escape ${ in existing values as $${ so they keep exporting exactly
+-- what they did before. Command vars aren't templates.
+UPDATE var SET value = replace(value, '${', '$${') WHERE kind = 'value';
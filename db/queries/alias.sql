@@ -0,0 +1,34 @@
+-- name: AliasCreate :exec
+INSERT INTO alias(
+    env_id, name, kind, value, comment, create_time, update_time, enabled
+) VALUES (
+    ?     , ?   , ?   , ?    , ?      , ?          , ?          , ?
+);
+
+-- name: AliasDelete :execrows
+DELETE FROM alias WHERE env_id = ? AND name = ?;
+
+-- name: AliasFindID :one
+SELECT alias_id FROM alias WHERE env_id = ? AND name = ?;
+
+-- name: AliasList :many
+SELECT * FROM alias
+WHERE env_id = ?
+ORDER BY name ASC;
+
+-- name: AliasShow :one
+SELECT *
+FROM alias
+WHERE env_id = ? AND name = ?;
+
+-- name: AliasUpdate :execrows
+UPDATE alias SET
+    env_id = COALESCE(sqlc.narg('env_id'), env_id),
+    name = COALESCE(sqlc.narg('name'), name),
+    kind = COALESCE(sqlc.narg('kind'), kind),
+    value = COALESCE(sqlc.narg('value'), value),
+    comment = COALESCE(sqlc.narg('comment'), comment),
+    create_time = COALESCE(sqlc.narg('create_time'), create_time),
+    update_time = COALESCE(sqlc.narg('update_time'), update_time),
+    enabled = COALESCE(sqlc.narg('enabled'), enabled)
+WHERE alias_id = sqlc.arg('alias_id');
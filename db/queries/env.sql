@@ -18,7 +18,7 @@ ORDER BY name ASC;
 
 -- name: EnvShow :one
 SELECT
-    name, comment, create_time, update_time, enabled
+    name, comment, create_time, update_time, enabled, on_enter, on_leave
 FROM env
 WHERE name = ?;
 
@@ -29,5 +29,7 @@ UPDATE env SET
     comment = COALESCE(sqlc.narg('comment'), comment),
     create_time = COALESCE(sqlc.narg('create_time'), create_time),
     update_time = COALESCE(sqlc.narg('update_time'), update_time),
-    enabled = COALESCE(sqlc.narg('enabled'), enabled)
+    enabled = COALESCE(sqlc.narg('enabled'), enabled),
+    on_enter = COALESCE(sqlc.narg('on_enter'), on_enter),
+    on_leave = COALESCE(sqlc.narg('on_leave'), on_leave)
 WHERE name = sqlc.arg('name');
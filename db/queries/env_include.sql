@@ -0,0 +1,19 @@
+-- name: EnvIncludeCreate :exec
+INSERT INTO env_include(
+    env_id, include_env_id, position
+) VALUES (
+    ?     , ?             , ?
+);
+
+-- name: EnvIncludeDelete :execrows
+DELETE FROM env_include WHERE env_id = ? AND include_env_id = ?;
+
+-- name: EnvIncludeList :many
+SELECT
+    env.name AS include_env_name,
+    env.enabled AS include_env_enabled,
+    env_include.position
+FROM env_include
+JOIN env ON env_include.include_env_id = env.env_id
+WHERE env_include.env_id = ?
+ORDER BY env_include.position ASC;
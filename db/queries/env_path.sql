@@ -0,0 +1,27 @@
+-- name: EnvPathCreate :exec
+INSERT INTO env_path(
+    env_id, path
+) VALUES (
+    ?     , ?
+);
+
+-- name: EnvPathDelete :execrows
+DELETE FROM env_path WHERE path = ?;
+
+-- name: EnvPathFindEnvName :one
+SELECT env.name FROM env_path
+JOIN env ON env_path.env_id = env.env_id
+WHERE env_path.path = ?;
+
+-- name: EnvPathList :many
+SELECT path FROM env_path
+WHERE env_id = ?
+ORDER BY path ASC;
+
+-- name: EnvPathListAll :many
+SELECT
+    env.name AS env_name,
+    env_path.path
+FROM env_path
+JOIN env ON env_path.env_id = env.env_id
+ORDER BY env_path.path ASC;
@@ -0,0 +1,23 @@
+-- name: EnvPatternCreate :exec
+INSERT INTO env_pattern(
+    env_id, pattern, kind
+) VALUES (
+    ?     , ?      , ?
+);
+
+-- name: EnvPatternDelete :execrows
+DELETE FROM env_pattern WHERE env_id = ? AND pattern = ?;
+
+-- name: EnvPatternList :many
+SELECT pattern, kind FROM env_pattern
+WHERE env_id = ?
+ORDER BY pattern ASC;
+
+-- name: EnvPatternListAll :many
+SELECT
+    env.name AS env_name,
+    env_pattern.pattern,
+    env_pattern.kind
+FROM env_pattern
+JOIN env ON env_pattern.env_id = env.env_id
+ORDER BY env.name ASC, env_pattern.pattern ASC;
@@ -0,0 +1,27 @@
+-- name: EnvRepoCreate :exec
+INSERT INTO env_repo(
+    env_id, repo
+) VALUES (
+    ?     , ?
+);
+
+-- name: EnvRepoDelete :execrows
+DELETE FROM env_repo WHERE repo = ?;
+
+-- name: EnvRepoFindEnvName :one
+SELECT env.name FROM env_repo
+JOIN env ON env_repo.env_id = env.env_id
+WHERE env_repo.repo = ?;
+
+-- name: EnvRepoList :many
+SELECT repo FROM env_repo
+WHERE env_id = ?
+ORDER BY repo ASC;
+
+-- name: EnvRepoListAll :many
+SELECT
+    env.name AS env_name,
+    env_repo.repo
+FROM env_repo
+JOIN env ON env_repo.env_id = env.env_id
+ORDER BY env_repo.repo ASC;
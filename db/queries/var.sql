@@ -1,8 +1,8 @@
 -- name: VarCreate :exec
 INSERT INTO var(
-    env_id, name, comment, create_time, update_time, value, enabled, completions
+    env_id, name, comment, create_time, update_time, value, enabled, completions, scope, kind, cache_ttl
 ) VALUES (
-    ?     , ?   , ?      , ?          , ?          , ?    , ?      , ?
+    ?     , ?   , ?      , ?          , ?          , ?    , ?      , ?          , ?    , ?   , ?
 );
 
 -- name: VarDelete :execrows
@@ -36,5 +36,8 @@ UPDATE var SET
     update_time = COALESCE(sqlc.narg('update_time'), update_time),
     value = COALESCE(sqlc.narg('value'), value),
     enabled = COALESCE(sqlc.narg('enabled'), enabled),
-    completions = COALESCE(sqlc.narg('completions'), completions)
+    completions = COALESCE(sqlc.narg('completions'), completions),
+    scope = COALESCE(sqlc.narg('scope'), scope),
+    kind = COALESCE(sqlc.narg('kind'), kind),
+    cache_ttl = COALESCE(sqlc.narg('cache_ttl'), cache_ttl)
 WHERE var_id = sqlc.arg('var_id');
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- name: VarCacheShow :one
+SELECT command, value, cache_time FROM var_cache WHERE var_id = ?;
+
+-- name: VarCacheSet :execrows
+INSERT INTO var_cache (
+    var_id, command, value, cache_time
+) VALUES (
+    ?     , ?      , ?    , ?
+)
+ON CONFLICT (var_id) DO UPDATE SET
+    command = excluded.command,
+    value = excluded.value,
+    cache_time = excluded.cache_time;
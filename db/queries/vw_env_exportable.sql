@@ -1,4 +1,4 @@
 -- name: EnvExportableList :many
-SELECT name, enabled, value FROM vw_env_exportable
+SELECT name, type, enabled, value, scope, kind, cache_ttl, var_env_name, var_name FROM vw_env_exportable
 WHERE env_id = ?
 ORDER BY type ASC, name ASC;
\ No newline at end of file
@@ -77,7 +77,7 @@ func (q *Queries) EnvFindID(ctx context.Context, name string) (int64, error) {
 }
 
 const envList = `-- name: EnvList :many
-SELECT env_id, name, comment, create_time, update_time, enabled FROM env
+SELECT env_id, name, comment, create_time, update_time, enabled, on_enter, on_leave FROM env
 ORDER BY name ASC
 `
 
@@ -97,6 +97,8 @@ func (q *Queries) EnvList(ctx context.Context) ([]Env, error) {
 			&i.CreateTime,
 			&i.UpdateTime,
 			&i.Enabled,
+			&i.OnEnter,
+			&i.OnLeave,
 		); err != nil {
 			return nil, err
 		}
@@ -113,7 +115,7 @@ func (q *Queries) EnvList(ctx context.Context) ([]Env, error) {
 
 const envShow = `-- name: EnvShow :one
 SELECT
-    name, comment, create_time, update_time, enabled
+    name, comment, create_time, update_time, enabled, on_enter, on_leave
 FROM env
 WHERE name = ?
 `
@@ -124,6 +126,8 @@ type EnvShowRow struct {
 	CreateTime string
 	UpdateTime string
 	Enabled    int64
+	OnEnter    string
+	OnLeave    string
 }
 
 func (q *Queries) EnvShow(ctx context.Context, name string) (EnvShowRow, error) {
@@ -135,6 +139,8 @@ func (q *Queries) EnvShow(ctx context.Context, name string) (EnvShowRow, error)
 		&i.CreateTime,
 		&i.UpdateTime,
 		&i.Enabled,
+		&i.OnEnter,
+		&i.OnLeave,
 	)
 	return i, err
 }
@@ -145,8 +151,10 @@ UPDATE env SET
     comment = COALESCE(?2, comment),
     create_time = COALESCE(?3, create_time),
     update_time = COALESCE(?4, update_time),
-    enabled = COALESCE(?5, enabled)
-WHERE name = ?6
+    enabled = COALESCE(?5, enabled),
+    on_enter = COALESCE(?6, on_enter),
+    on_leave = COALESCE(?7, on_leave)
+WHERE name = ?8
 `
 
 type EnvUpdateParams struct {
@@ -155,6 +163,8 @@ type EnvUpdateParams struct {
 	CreateTime *string
 	UpdateTime *string
 	Enabled    *int64
+	OnEnter    *string
+	OnLeave    *string
 	Name       string
 }
 
@@ -166,6 +176,8 @@ func (q *Queries) EnvUpdate(ctx context.Context, arg EnvUpdateParams) (int64, er
 		arg.CreateTime,
 		arg.UpdateTime,
 		arg.Enabled,
+		arg.OnEnter,
+		arg.OnLeave,
 		arg.Name,
 	)
 	if err != nil {
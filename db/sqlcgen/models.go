@@ -4,6 +4,18 @@
 
 package sqlcgen
 
+type Alias struct {
+	AliasID    int64
+	EnvID      int64
+	Name       string
+	Kind       string
+	Value      string
+	Comment    string
+	CreateTime string
+	UpdateTime string
+	Enabled    int64
+}
+
 type Env struct {
 	EnvID      int64
 	Name       string
@@ -11,6 +23,34 @@ type Env struct {
 	CreateTime string
 	UpdateTime string
 	Enabled    int64
+	OnEnter    string
+	OnLeave    string
+}
+
+type EnvInclude struct {
+	EnvIncludeID int64
+	EnvID        int64
+	IncludeEnvID int64
+	Position     int64
+}
+
+type EnvPath struct {
+	EnvPathID int64
+	EnvID     int64
+	Path      string
+}
+
+type EnvPattern struct {
+	EnvPatternID int64
+	EnvID        int64
+	Pattern      string
+	Kind         string
+}
+
+type EnvRepo struct {
+	EnvRepoID int64
+	EnvID     int64
+	Repo      string
 }
 
 type Var struct {
@@ -23,6 +63,16 @@ type Var struct {
 	Value       string
 	Enabled     int64
 	Completions string
+	Scope       string
+	Kind        string
+	CacheTtl    int64
+}
+
+type VarCache struct {
+	VarID     int64
+	Command   string
+	Value     string
+	CacheTime string
 }
 
 type VarRef struct {
@@ -46,6 +96,11 @@ type VwEnvExportable struct {
 	Value      string
 	CreateTime string
 	UpdateTime string
+	Scope      string
+	Kind       string
+	CacheTtl   int64
+	VarEnvName string
+	VarName    string
 }
 
 type VwEnvVarVarRefUniqueName struct {
@@ -64,6 +119,7 @@ type VwVarExpanded struct {
 	UpdateTime  string
 	Enabled     int64
 	Completions string
+	Scope       string
 }
 
 type VwVarRefExpanded struct {
@@ -11,9 +11,9 @@ import (
 
 const varCreate = `-- name: VarCreate :exec
 INSERT INTO var(
-    env_id, name, comment, create_time, update_time, value, enabled, completions
+    env_id, name, comment, create_time, update_time, value, enabled, completions, scope, kind, cache_ttl
 ) VALUES (
-    ?     , ?   , ?      , ?          , ?          , ?    , ?      , ?
+    ?     , ?   , ?      , ?          , ?          , ?    , ?      , ?          , ?    , ?   , ?
 )
 `
 
@@ -26,6 +26,9 @@ type VarCreateParams struct {
 	Value       string
 	Enabled     int64
 	Completions string
+	Scope       string
+	Kind        string
+	CacheTtl    int64
 }
 
 func (q *Queries) VarCreate(ctx context.Context, arg VarCreateParams) error {
@@ -38,6 +41,9 @@ func (q *Queries) VarCreate(ctx context.Context, arg VarCreateParams) error {
 		arg.Value,
 		arg.Enabled,
 		arg.Completions,
+		arg.Scope,
+		arg.Kind,
+		arg.CacheTtl,
 	)
 	return err
 }
@@ -60,7 +66,7 @@ func (q *Queries) VarDelete(ctx context.Context, arg VarDeleteParams) (int64, er
 }
 
 const varFindByID = `-- name: VarFindByID :one
-SELECT env.name AS env_name, var.var_id, var.env_id, var.name, var.comment, var.create_time, var.update_time, var.value, var.enabled, var.completions
+SELECT env.name AS env_name, var.var_id, var.env_id, var.name, var.comment, var.create_time, var.update_time, var.value, var.enabled, var.completions, var.scope, var.kind, var.cache_ttl
 FROM var
 JOIN env ON var.env_id = env.env_id
 WHERE var.var_id = ?
@@ -77,6 +83,9 @@ type VarFindByIDRow struct {
 	Value       string
 	Enabled     int64
 	Completions string
+	Scope       string
+	Kind        string
+	CacheTtl    int64
 }
 
 func (q *Queries) VarFindByID(ctx context.Context, varID int64) (VarFindByIDRow, error) {
@@ -93,6 +102,9 @@ func (q *Queries) VarFindByID(ctx context.Context, varID int64) (VarFindByIDRow,
 		&i.Value,
 		&i.Enabled,
 		&i.Completions,
+		&i.Scope,
+		&i.Kind,
+		&i.CacheTtl,
 	)
 	return i, err
 }
@@ -114,7 +126,7 @@ func (q *Queries) VarFindID(ctx context.Context, arg VarFindIDParams) (int64, er
 }
 
 const varList = `-- name: VarList :many
-SELECT var_id, env_id, name, comment, create_time, update_time, value, enabled, completions FROM var
+SELECT var_id, env_id, name, comment, create_time, update_time, value, enabled, completions, scope, kind, cache_ttl FROM var
 WHERE env_id = ?
 ORDER BY name ASC
 `
@@ -138,6 +150,9 @@ func (q *Queries) VarList(ctx context.Context, envID int64) ([]Var, error) {
 			&i.Value,
 			&i.Enabled,
 			&i.Completions,
+			&i.Scope,
+			&i.Kind,
+			&i.CacheTtl,
 		); err != nil {
 			return nil, err
 		}
@@ -153,7 +168,7 @@ func (q *Queries) VarList(ctx context.Context, envID int64) ([]Var, error) {
 }
 
 const varShow = `-- name: VarShow :one
-SELECT var_id, env_id, name, comment, create_time, update_time, value, enabled, completions
+SELECT var_id, env_id, name, comment, create_time, update_time, value, enabled, completions, scope, kind, cache_ttl
 FROM var
 WHERE env_id = ? AND name = ?
 `
@@ -176,6 +191,9 @@ func (q *Queries) VarShow(ctx context.Context, arg VarShowParams) (Var, error) {
 		&i.Value,
 		&i.Enabled,
 		&i.Completions,
+		&i.Scope,
+		&i.Kind,
+		&i.CacheTtl,
 	)
 	return i, err
 }
@@ -189,8 +207,11 @@ UPDATE var SET
     update_time = COALESCE(?5, update_time),
     value = COALESCE(?6, value),
     enabled = COALESCE(?7, enabled),
-    completions = COALESCE(?8, completions)
-WHERE var_id = ?9
+    completions = COALESCE(?8, completions),
+    scope = COALESCE(?9, scope),
+    kind = COALESCE(?10, kind),
+    cache_ttl = COALESCE(?11, cache_ttl)
+WHERE var_id = ?12
 `
 
 type VarUpdateParams struct {
@@ -202,6 +223,9 @@ type VarUpdateParams struct {
 	Value       *string
 	Enabled     *int64
 	Completions *string
+	Scope       *string
+	Kind        *string
+	CacheTtl    *int64
 	VarID       int64
 }
 
@@ -215,6 +239,9 @@ func (q *Queries) VarUpdate(ctx context.Context, arg VarUpdateParams) (int64, er
 		arg.Value,
 		arg.Enabled,
 		arg.Completions,
+		arg.Scope,
+		arg.Kind,
+		arg.CacheTtl,
 		arg.VarID,
 	)
 	if err != nil {
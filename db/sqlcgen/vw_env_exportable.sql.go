@@ -10,15 +10,21 @@ import (
 )
 
 const envExportableList = `-- name: EnvExportableList :many
-SELECT name, enabled, value FROM vw_env_exportable
+SELECT name, type, enabled, value, scope, kind, cache_ttl, var_env_name, var_name FROM vw_env_exportable
 WHERE env_id = ?
 ORDER BY type ASC, name ASC
 `
 
 type EnvExportableListRow struct {
-	Name    string
-	Enabled int64
-	Value   string
+	Name       string
+	Type       string
+	Enabled    int64
+	Value      string
+	Scope      string
+	Kind       string
+	CacheTtl   int64
+	VarEnvName string
+	VarName    string
 }
 
 func (q *Queries) EnvExportableList(ctx context.Context, envID int64) ([]EnvExportableListRow, error) {
@@ -30,7 +36,17 @@ func (q *Queries) EnvExportableList(ctx context.Context, envID int64) ([]EnvExpo
 	var items []EnvExportableListRow
 	for rows.Next() {
 		var i EnvExportableListRow
-		if err := rows.Scan(&i.Name, &i.Enabled, &i.Value); err != nil {
+		if err := rows.Scan(
+			&i.Name,
+			&i.Type,
+			&i.Enabled,
+			&i.Value,
+			&i.Scope,
+			&i.Kind,
+			&i.CacheTtl,
+			&i.VarEnvName,
+			&i.VarName,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -15,9 +15,19 @@ func buildApp() *warg.App {
 		version,
 		warg.NewSection(
 			"Manage Environmental secrets centrally",
+			warg.NewSubSection(
+				"alias",
+				"Shell aliases and functions owned by this environment",
+				warg.SubCmd("create", cli.AliasCreateCmd()),
+				warg.SubCmd("delete", cli.AliasDeleteCmd()),
+				warg.SubCmd("show", cli.AliasShowCmd()),
+				warg.SubCmd("update", cli.AliasUpdateCmd()),
+			),
 			warg.NewSubSection(
 				"completion",
 				"Print completion scripts",
+				warg.SubCmd("bash", cli.CompletionBashCmd()),
+				warg.SubCmd("fish", cli.CompletionFishCmd()),
 				warg.SubCmd("zsh", cli.CompletionZshCmd()),
 			),
 			warg.NewSubSection(
@@ -26,18 +36,100 @@ func buildApp() *warg.App {
 				warg.SubCmd("create", cli.EnvCreateCmd()),
 				warg.SubCmd("delete", cli.EnvDeleteCmd()),
 				warg.SubCmd("list", cli.EnvListCmd()),
+				warg.SubCmd("match", cli.EnvMatchCmd()),
+				warg.SubCmd("normalize", cli.EnvNormalizeCmd()),
+				warg.SubCmd("prune", cli.EnvPruneCmd()),
+				warg.SubCmd("relocate", cli.EnvRelocateCmd()),
 				warg.SubCmd("update", cli.EnvUpdateCmd()),
 				warg.SubCmd("show", cli.EnvShowCmd()),
+				warg.SubCmd("trust", cli.EnvTrustCmd()),
+				warg.NewSubSection(
+					"include",
+					"Envs whose vars and refs this env inherits",
+					warg.SubCmd("create", cli.EnvIncludeCreateCmd()),
+					warg.SubCmd("delete", cli.EnvIncludeDeleteCmd()),
+				),
+				warg.NewSubSection(
+					"path",
+					"Directories activating this env besides the one it's named after",
+					warg.SubCmd("add", cli.EnvPathAddCmd()),
+					warg.SubCmd("list", cli.EnvPathListCmd()),
+					warg.SubCmd("remove", cli.EnvPathRemoveCmd()),
+				),
+				warg.NewSubSection(
+					"pattern",
+					"Path patterns activating this env",
+					warg.SubCmd("create", cli.EnvPatternCreateCmd()),
+					warg.SubCmd("delete", cli.EnvPatternDeleteCmd()),
+				),
+				warg.NewSubSection(
+					"repo",
+					"Git repos activating this env in every clone and worktree",
+					warg.SubCmd("add", cli.EnvRepoAddCmd()),
+					warg.SubCmd("remove", cli.EnvRepoRemoveCmd()),
+				),
 			),
 			warg.NewSubSection(
 				"shell",
 				"Manipulate the current shell",
+				warg.SubCmd("prompt", cli.ShellPromptCmd()),
+				warg.SubCmd("spawn", cli.ShellSpawnCmd()),
+				warg.NewSubSection(
+					"bash",
+					"Bash-specific commands",
+					warg.SubCmd("chdir", cli.ShellBashChdirCmd()),
+					warg.SubCmd("init", cli.ShellBashInitCmd()),
+					warg.SubCmd("export", cli.ShellBashExportCmd()),
+					warg.SubCmd("pop", cli.ShellBashPopCmd()),
+					warg.SubCmd("push", cli.ShellBashPushCmd()),
+					warg.SubCmd("stack", cli.ShellBashStackCmd()),
+					warg.SubCmd("unexport", cli.ShellBashUnexportCmd()),
+				),
+				warg.NewSubSection(
+					"fish",
+					"Fish-specific commands",
+					warg.SubCmd("chdir", cli.ShellFishChdirCmd()),
+					warg.SubCmd("init", cli.ShellFishInitCmd()),
+					warg.SubCmd("export", cli.ShellFishExportCmd()),
+					warg.SubCmd("pop", cli.ShellFishPopCmd()),
+					warg.SubCmd("push", cli.ShellFishPushCmd()),
+					warg.SubCmd("stack", cli.ShellFishStackCmd()),
+					warg.SubCmd("unexport", cli.ShellFishUnexportCmd()),
+				),
+				warg.NewSubSection(
+					"nu",
+					"Nushell-specific commands",
+					warg.SubCmd("chdir", cli.ShellNuChdirCmd()),
+					warg.SubCmd("init", cli.ShellNuInitCmd()),
+					warg.SubCmd("export", cli.ShellNuExportCmd()),
+					warg.SubCmd("pop", cli.ShellNuPopCmd()),
+					warg.SubCmd("push", cli.ShellNuPushCmd()),
+					warg.SubCmd("stack", cli.ShellNuStackCmd()),
+					warg.SubCmd("unexport", cli.ShellNuUnexportCmd()),
+				),
+				warg.NewSubSection(
+					"pwsh",
+					"PowerShell-specific commands",
+					warg.SubCmd("chdir", cli.ShellPwshChdirCmd()),
+					warg.SubCmd("init", cli.ShellPwshInitCmd()),
+					warg.SubCmd("export", cli.ShellPwshExportCmd()),
+					warg.SubCmd("pop", cli.ShellPwshPopCmd()),
+					warg.SubCmd("push", cli.ShellPwshPushCmd()),
+					warg.SubCmd("stack", cli.ShellPwshStackCmd()),
+					warg.SubCmd("unexport", cli.ShellPwshUnexportCmd()),
+				),
 				warg.NewSubSection(
 					"zsh",
 					"Zsh-specific commands",
 					warg.SubCmd("chdir", cli.ShellZshChdirCmd()),
+					warg.SubCmd("expire", cli.ShellZshExpireCmd()),
 					warg.SubCmd("init", cli.ShellZshInitCmd()),
 					warg.SubCmd("export", cli.ShellZshExportCmd()),
+					warg.SubCmd("pop", cli.ShellZshPopCmd()),
+					warg.SubCmd("push", cli.ShellZshPushCmd()),
+					warg.SubCmd("reload", cli.ShellZshReloadCmd()),
+					warg.SubCmd("scoped-commands", cli.ShellZshScopedCommandsCmd()),
+					warg.SubCmd("stack", cli.ShellZshStackCmd()),
 					warg.SubCmd("unexport", cli.ShellZshUnexportCmd()),
 				),
 			),
@@ -58,6 +150,7 @@ func buildApp() *warg.App {
 				),
 			),
 			warg.SubCmd("exec", cli.ExecCmd()),
+			warg.SubCmd("status", cli.StatusCmd()),
 		),
 		warg.SkipCompletionCmds(),
 	)
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"go.bbkane.com/enventory/cli"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/metadata"
+)
+
+func TestAlias(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	// the shell is in directory a. Since its aliases were defined, k changed,
+	// f became a function, and gone was deleted.
+	definedEnv := map[string]string{
+		"ENVENTORY_ALIASES": `{"f":{"env":"a","kind":"alias","fp":"cba06b5736faf67e"},"gone":{"env":"a","kind":"function","fp":"cba06b5736faf67e"},"k":{"env":"a","kind":"alias","fp":"cba06b5736faf67e"}}`,
+	}
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+		// shellEnv is the environment of the shell running the command
+		shellEnv map[string]string
+	}{
+		{
+			name:            "01_envCreate",
+			args:            envCreateTestCmd(dbName, "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "02_aliasCreate",
+			args: new(testCmdBuilder).Strs("alias", "create").
+				EnvName("a").Name("k").Strs("--value", "kubectl --context prod").
+				ZeroTimes().Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "03_aliasCreateFunction",
+			args: new(testCmdBuilder).Strs("alias", "create").
+				EnvName("a").Name("f").Strs("--kind", "function", "--value", `echo "f: $@";`).
+				ZeroTimes().Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "04_aliasCreateBadName",
+			args: new(testCmdBuilder).Strs("alias", "create").
+				EnvName("a").Name("k;rm").Strs("--value", "ls").
+				ZeroTimes().Finish(dbName),
+			expectActionErr: true,
+			shellEnv:        nil,
+		},
+		{
+			name: "05_aliasShow",
+			args: new(testCmdBuilder).Strs("alias", "show").
+				EnvName("a").Name("k").Tz().Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "06_envShow",
+			args:            envShowTestCmd(dbName, "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "07_zshExport",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "export").
+				EnvName("a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "08_fishExport",
+			args: new(testCmdBuilder).Strs("shell", "fish", "export").
+				EnvName("a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "09_pwshExport",
+			args: new(testCmdBuilder).Strs("shell", "pwsh", "export").
+				EnvName("a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "10_nuExportSkipsAliases",
+			args: new(testCmdBuilder).Strs("shell", "nu", "export").
+				EnvName("a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "11_zshChdirRedefines",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "a", "--new", "a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        definedEnv,
+		},
+		{
+			name: "12_zshChdirRemoves",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "a", "--new", "none").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        definedEnv,
+		},
+		{
+			name: "13_zshUnexport",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "unexport").
+				EnvName("a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        definedEnv,
+		},
+		{
+			name: "14_aliasUpdate",
+			args: new(testCmdBuilder).Strs("alias", "update").
+				EnvName("a").Name("k").Strs("--value", "kubectl --context dev").
+				Confirm(false).Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "15_aliasDelete",
+			args: new(testCmdBuilder).Strs("alias", "delete").
+				EnvName("a").Name("f").Confirm(false).Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "16_envShowAfter",
+			args:            envShowTestCmd(dbName, "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(tt.shellEnv))
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             buildApp(),
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithMetadata(md),
+			)
+		})
+	}
+}
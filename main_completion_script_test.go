@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompletionScript tests the completion scripts and, for shells that are
+// installed, that they parse
+func TestCompletionScript(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	tests := []struct {
+		shell string
+		// syntaxCheck parses a script without running it
+		syntaxCheck []string
+	}{
+		{shell: "bash", syntaxCheck: []string{"bash", "-n"}},
+		{shell: "fish", syntaxCheck: []string{"fish", "--no-execute"}},
+		{shell: "zsh", syntaxCheck: []string{"zsh", "-n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			goldenTest(t, testcase{
+				name:            tt.shell,
+				args:            []string{"completion", tt.shell},
+				expectActionErr: false,
+			}, updateGolden)
+
+			if _, err := exec.LookPath(tt.syntaxCheck[0]); err != nil {
+				t.Skipf("%s not installed, skipping syntax check", tt.syntaxCheck[0])
+			}
+			script := filepath.Join("testdata", t.Name(), "stdout.golden.txt")
+			args := append(tt.syntaxCheck[1:], script)
+			output, err := exec.Command(tt.syntaxCheck[0], args...).CombinedOutput()
+			require.NoError(t, err, string(output))
+		})
+	}
+}
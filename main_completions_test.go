@@ -48,6 +48,7 @@ func TestMainCompletions(t *testing.T) {
 		UpdateTime:  time.Time{},
 		Enabled:     true,
 		Completions: []string{"completion1", "completion2"},
+		Scope:       nil,
 	})
 	require.NoError(t, err)
 
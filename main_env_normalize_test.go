@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.bbkane.com/enventory/app"
+	"go.bbkane.com/enventory/models"
+)
+
+func TestEnvNormalize(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	// the CLI canonicalizes names, so create the envs older versions would
+	// have created with the service directly
+	ctx := context.Background()
+	service, err := app.NewEnvService(ctx, dbName)
+	require.NoError(t, err)
+	for _, name := range []string{"/norm/a/", "/norm/b", "/norm/b/", "/norm//c", "notapath"} {
+		_, err = service.EnvCreate(ctx, models.EnvCreateArgs{Name: name, Comment: "", CreateTime: time.Time{}, UpdateTime: time.Time{}, Enabled: true})
+		require.NoError(t, err)
+	}
+	require.NoError(t, service.EnvPathAdd(ctx, models.EnvPath{EnvName: "/norm//c", Path: "/norm/d/"}))
+
+	tests := []testcase{
+		{
+			name: "01_normalizeDryRun",
+			args: new(testCmdBuilder).Strs("env", "normalize").
+				Strs("--dry-run", "true").Finish(dbName),
+			expectActionErr: true,
+		},
+		{
+			name:            "02_oldNameShow",
+			args:            envShowTestCmd(dbName, "/norm//c"),
+			expectActionErr: false,
+		},
+		{
+			name: "03_collisionDelete",
+			args: new(testCmdBuilder).Strs("env", "delete").
+				Name("/norm/b/").Confirm(false).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "04_normalize",
+			args: new(testCmdBuilder).Strs("env", "normalize").
+				Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "05_envList",
+			args: new(testCmdBuilder).Strs("env", "list").
+				Tz().Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "06_pathList",
+			args: new(testCmdBuilder).Strs("env", "path", "list").
+				EnvName("/norm/c/").Finish(dbName),
+			expectActionErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenTest(t, tt, updateGolden)
+		})
+	}
+}
+
+func TestShellZshChdirSymlink(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+	dir := t.TempDir()
+	real := filepath.Join(dir, "real")
+	link := filepath.Join(dir, "link")
+	require.NoError(t, os.Mkdir(real, 0o755))
+	require.NoError(t, os.Symlink(real, link))
+	canonicalReal, err := filepath.EvalSymlinks(real)
+	require.NoError(t, err)
+
+	tests := []testcase{
+		{
+			name:            "01_envCreate",
+			args:            envCreateTestCmd(dbName, "proj"),
+			expectActionErr: false,
+		},
+		{
+			name:            "02_varCreate",
+			args:            varCreateTestCmd(dbName, "proj", "X", "proj"),
+			expectActionErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenTest(t, tt, updateGolden)
+		})
+	}
+
+	// bind the directory directly so goldens don't depend on the temp dir
+	ctx := context.Background()
+	service, err := app.NewEnvService(ctx, dbName)
+	require.NoError(t, err)
+	require.NoError(t, service.EnvPathAdd(ctx, models.EnvPath{EnvName: "proj", Path: canonicalReal}))
+
+	tests = []testcase{
+		{
+			name: "03_chdirLink",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", dir, "--new", link).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "04_chdirLinkToReal",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", link, "--new", real+"/").Finish(dbName),
+			expectActionErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenTest(t, tt, updateGolden)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestEnvPath(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	tests := []testcase{
+		{
+			name:            "01_projEnvCreate",
+			args:            envCreateTestCmd(dbName, "proj"),
+			expectActionErr: false,
+		},
+		{
+			name:            "02_projVarCreate",
+			args:            varCreateTestCmd(dbName, "proj", "X", "proj"),
+			expectActionErr: false,
+		},
+		{
+			name: "03_pathAddA",
+			args: new(testCmdBuilder).Strs("env", "path", "add").
+				EnvName("proj").Strs("--path", "/clones/a").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "04_pathAddB",
+			args: new(testCmdBuilder).Strs("env", "path", "add").
+				EnvName("proj").Strs("--path", "/clones/b/").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "05_pathAddDuplicate",
+			args: new(testCmdBuilder).Strs("env", "path", "add").
+				EnvName("proj").Strs("--path", "/clones/a").Finish(dbName),
+			expectActionErr: true,
+		},
+		{
+			name:            "06_exactEnvCreate",
+			args:            envCreateTestCmd(dbName, "/clones/b"),
+			expectActionErr: false,
+		},
+		{
+			name:            "07_exactVarCreate",
+			args:            varCreateTestCmd(dbName, "/clones/b", "X", "exact"),
+			expectActionErr: false,
+		},
+		{
+			name:            "08_projEnvShow",
+			args:            envShowTestCmd(dbName, "proj"),
+			expectActionErr: false,
+		},
+		{
+			name: "09_pathList",
+			args: new(testCmdBuilder).Strs("env", "path", "list").
+				EnvName("proj").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "10_matchPath",
+			args: new(testCmdBuilder).Strs("env", "match").
+				Strs("--path", "/clones/b").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "11_chdirPath",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "/other", "--new", "/clones/a").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "12_exportPath",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "export").
+				EnvName("/clones/a").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "13_pathRemove",
+			args: new(testCmdBuilder).Strs("env", "path", "remove").
+				Strs("--path", "/clones/a").Confirm(false).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "14_pathRemoveNonexisting",
+			args: new(testCmdBuilder).Strs("env", "path", "remove").
+				Strs("--path", "/clones/a").Confirm(false).Finish(dbName),
+			expectActionErr: true,
+		},
+		{
+			name: "15_pathList",
+			args: new(testCmdBuilder).Strs("env", "path", "list").
+				EnvName("proj").Finish(dbName),
+			expectActionErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenTest(t, tt, updateGolden)
+		})
+	}
+}
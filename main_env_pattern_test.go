@@ -0,0 +1,213 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.bbkane.com/enventory/cli"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/metadata"
+)
+
+func TestEnvPattern(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	tests := []testcase{
+		{
+			name:            "01_backendEnvCreate",
+			args:            envCreateTestCmd(dbName, "backend"),
+			expectActionErr: false,
+		},
+		{
+			name:            "02_backendVarCreate",
+			args:            varCreateTestCmd(dbName, "backend", "X", "backend"),
+			expectActionErr: false,
+		},
+		{
+			name: "03_backendPatternCreate",
+			args: new(testCmdBuilder).Strs("env", "pattern", "create").
+				EnvName("backend").Strs("--pattern", "/work/*/backend").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name:            "04_workEnvCreate",
+			args:            envCreateTestCmd(dbName, "work"),
+			expectActionErr: false,
+		},
+		{
+			name:            "05_workVarCreate",
+			args:            varCreateTestCmd(dbName, "work", "Y", "work"),
+			expectActionErr: false,
+		},
+		{
+			name: "06_workPatternCreateRegex",
+			args: new(testCmdBuilder).Strs("env", "pattern", "create").
+				EnvName("work").Strs("--pattern", "/work/[^/]+(/.*)?", "--kind", "regex").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "07_workPatternCreateInvalidRegex",
+			args: new(testCmdBuilder).Strs("env", "pattern", "create").
+				EnvName("work").Strs("--pattern", "/work/(", "--kind", "regex").Finish(dbName),
+			expectActionErr: true,
+		},
+		{
+			name:            "08_exactEnvCreate",
+			args:            envCreateTestCmd(dbName, "/work/a/backend"),
+			expectActionErr: false,
+		},
+		{
+			name:            "09_exactVarCreate",
+			args:            varCreateTestCmd(dbName, "/work/a/backend", "X", "exact"),
+			expectActionErr: false,
+		},
+		{
+			name:            "10_backendEnvShow",
+			args:            envShowTestCmd(dbName, "backend"),
+			expectActionErr: false,
+		},
+		{
+			name: "11_matchExact",
+			args: new(testCmdBuilder).Strs("env", "match").
+				Strs("--path", "/work/a/backend").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "12_matchPatterns",
+			args: new(testCmdBuilder).Strs("env", "match").
+				Strs("--path", "/work/b/backend").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "13_chdirExact",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "/other", "--new", "/work/a/backend").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "14_chdirPatterns",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "/other", "--new", "/work/b/backend").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "15_backendPatternDelete",
+			args: new(testCmdBuilder).Strs("env", "pattern", "delete").
+				EnvName("backend").Strs("--pattern", "/work/*/backend").Confirm(false).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "16_matchPatternsAfterDelete",
+			args: new(testCmdBuilder).Strs("env", "match").
+				Strs("--path", "/work/b/backend").Finish(dbName),
+			expectActionErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenTest(t, tt, updateGolden)
+		})
+	}
+}
+
+func TestEnvPatternHome(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	// $HOME is a symlink, so canonical paths under it differ from $HOME
+	tmp := t.TempDir()
+	realHome := filepath.Join(tmp, "real")
+	linkHome := filepath.Join(tmp, "link")
+	require.NoError(t, os.MkdirAll(filepath.Join(realHome, "work", "a", "svc"), 0o755))
+	require.NoError(t, os.Symlink(realHome, linkHome))
+	svcDir := filepath.Join(linkHome, "work", "a", "svc")
+
+	homeEnv := map[string]string{"HOME": linkHome}
+	caseInsensitiveEnv := map[string]string{"HOME": linkHome, "ENVENTORY_CASE_INSENSITIVE_PATHS": "true"}
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+		// shellEnv is the environment of the shell running the command
+		shellEnv map[string]string
+	}{
+		{
+			name:            "01_globEnvCreate",
+			args:            envCreateTestCmd(dbName, "glob"),
+			expectActionErr: false,
+			shellEnv:        homeEnv,
+		},
+		{
+			name:            "02_globVarCreate",
+			args:            varCreateTestCmd(dbName, "glob", "X", "glob"),
+			expectActionErr: false,
+			shellEnv:        homeEnv,
+		},
+		{
+			name: "03_globPatternCreate",
+			args: new(testCmdBuilder).Strs("env", "pattern", "create").
+				EnvName("glob").Strs("--pattern", "~/Work/*/svc").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        homeEnv,
+		},
+		{
+			name:            "04_regexEnvCreate",
+			args:            envCreateTestCmd(dbName, "regex"),
+			expectActionErr: false,
+			shellEnv:        homeEnv,
+		},
+		{
+			name:            "05_regexVarCreate",
+			args:            varCreateTestCmd(dbName, "regex", "Y", "regex"),
+			expectActionErr: false,
+			shellEnv:        homeEnv,
+		},
+		{
+			name: "06_regexPatternCreate",
+			args: new(testCmdBuilder).Strs("env", "pattern", "create").
+				EnvName("regex").Strs("--pattern", "~/work/.*", "--kind", "regex").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        homeEnv,
+		},
+		{
+			name: "07_matchCaseSensitive",
+			args: new(testCmdBuilder).Strs("env", "match").
+				Strs("--path", svcDir).Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        homeEnv,
+		},
+		{
+			name: "08_chdirCaseInsensitive",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "/other", "--new", svcDir).Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        caseInsensitiveEnv,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(tt.shellEnv))
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             buildApp(),
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithMetadata(md),
+			)
+		})
+	}
+}
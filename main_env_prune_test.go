@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestEnvPrune(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	tests := []testcase{
+		{
+			name:            "01_goneEnvCreate",
+			args:            envCreateTestCmd(dbName, "/gone/a"),
+			expectActionErr: false,
+		},
+		{
+			name:            "02_goneVarCreate",
+			args:            varCreateTestCmd(dbName, "/gone/a", "X", "a"),
+			expectActionErr: false,
+		},
+		{
+			name:            "03_goneRefedEnvCreate",
+			args:            envCreateTestCmd(dbName, "/gone/b"),
+			expectActionErr: false,
+		},
+		{
+			name:            "04_goneRefedVarCreate",
+			args:            varCreateTestCmd(dbName, "/gone/b", "Y", "b"),
+			expectActionErr: false,
+		},
+		{
+			name:            "05_goneRefCreate",
+			args:            varRefCreateTestCmd(dbName, "/gone/a", "Y_FROM_B", "/gone/b", "Y"),
+			expectActionErr: false,
+		},
+		{
+			name:            "06_keptEnvCreate",
+			args:            envCreateTestCmd(dbName, "kept"),
+			expectActionErr: false,
+		},
+		{
+			name:            "07_keptVarCreate",
+			args:            varCreateTestCmd(dbName, "kept", "Z", "kept"),
+			expectActionErr: false,
+		},
+		{
+			name:            "08_goneBlockedEnvCreate",
+			args:            envCreateTestCmd(dbName, "/gone/c"),
+			expectActionErr: false,
+		},
+		{
+			name:            "09_goneBlockedVarCreate",
+			args:            varCreateTestCmd(dbName, "/gone/c", "W", "c"),
+			expectActionErr: false,
+		},
+		{
+			name:            "10_keptRefCreate",
+			args:            varRefCreateTestCmd(dbName, "kept", "W_FROM_C", "/gone/c", "W"),
+			expectActionErr: false,
+		},
+		{
+			name:            "11_existingEnvCreate",
+			args:            envCreateTestCmd(dbName, "/"),
+			expectActionErr: false,
+		},
+		{
+			name: "12_pruneDryRun",
+			args: new(testCmdBuilder).Strs("env", "prune").
+				Strs("--dry-run", "true").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "13_prune",
+			args: new(testCmdBuilder).Strs("env", "prune").
+				Confirm(false).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "14_pruneExpr",
+			args: new(testCmdBuilder).Strs("env", "prune").
+				Strs("--expr", `filter(Envs, .Name == "kept")`).Confirm(false).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "15_pruneNothing",
+			args: new(testCmdBuilder).Strs("env", "prune").
+				Confirm(false).Finish(dbName),
+			expectActionErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenTest(t, tt, updateGolden)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.bbkane.com/enventory/cli"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/metadata"
+)
+
+func TestEnvRelocate(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	tests := []testcase{
+		{
+			name:            "01_envCreateTilde",
+			args:            envCreateTestCmd(dbName, "~/src/a"),
+			expectActionErr: false,
+		},
+		{
+			name:            "02_varCreate",
+			args:            varCreateTestCmd(dbName, "~/src/a", "X", "a"),
+			expectActionErr: false,
+		},
+		{
+			name:            "03_envCreateUnderHome",
+			args:            envCreateTestCmd(dbName, filepath.Join(home, "src", "b")),
+			expectActionErr: false,
+		},
+		{
+			name:            "04_envCreateOutsideHome",
+			args:            envCreateTestCmd(dbName, "/srv/c"),
+			expectActionErr: false,
+		},
+		{
+			name: "05_pathAdd",
+			args: new(testCmdBuilder).Strs("env", "path", "add").
+				EnvName("~/src/a").Strs("--path", filepath.Join(home, "src", "a2")).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "06_chdirAbsolute",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "/other", "--new", filepath.Join(home, "src", "a")).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "07_relocateDryRun",
+			args: new(testCmdBuilder).Strs("env", "relocate").
+				Strs("--from-prefix", "~/src", "--to-prefix", "~/code", "--dry-run", "true").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "08_relocate",
+			args: new(testCmdBuilder).Strs("env", "relocate").
+				Strs("--from-prefix", "~/src", "--to-prefix", "~/code").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "09_relocateNothing",
+			args: new(testCmdBuilder).Strs("env", "relocate").
+				Strs("--from-prefix", "~/src", "--to-prefix", "~/code").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "10_relocateIntoHome",
+			args: new(testCmdBuilder).Strs("env", "relocate").
+				Strs("--from-prefix", "/srv", "--to-prefix", "~/code").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name:            "11_envCreateColliding",
+			args:            envCreateTestCmd(dbName, "~/other/a"),
+			expectActionErr: false,
+		},
+		{
+			name: "12_relocateCollision",
+			args: new(testCmdBuilder).Strs("env", "relocate").
+				Strs("--from-prefix", "~/other", "--to-prefix", "~/code").Finish(dbName),
+			expectActionErr: true,
+		},
+		{
+			name: "13_envList",
+			args: new(testCmdBuilder).Strs("env", "list").
+				Tz().Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "14_pathList",
+			args: new(testCmdBuilder).Strs("env", "path", "list").
+				EnvName("~/code/a").Finish(dbName),
+			expectActionErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenTest(t, tt, updateGolden)
+		})
+	}
+}
+
+func TestEnvRelocateFromPrefix(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	caseInsensitiveEnv := map[string]string{"ENVENTORY_CASE_INSENSITIVE_PATHS": "true"}
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+		// shellEnv is the environment of the shell running the command
+		shellEnv map[string]string
+	}{
+		{
+			name:            "01_envCreateMixedCase",
+			args:            envCreateTestCmd(dbName, "/Old/Proj"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			// the old prefix describes names in the database, so it isn't
+			// lowercased like names on this machine
+			name: "02_relocateCaseInsensitive",
+			args: new(testCmdBuilder).Strs("env", "relocate").
+				Strs("--from-prefix", "/Old/", "--to-prefix", "/new").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        caseInsensitiveEnv,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(tt.shellEnv))
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             buildApp(),
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithMetadata(md),
+			)
+		})
+	}
+}
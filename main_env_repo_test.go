@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createTestGitRepo lays out a git repo with an origin remote and a linked
+// worktree the way git does, without needing git installed. It returns the
+// clone and worktree directories.
+func createTestGitRepo(t *testing.T, originURL string) (string, string) {
+	root := t.TempDir()
+	clone := filepath.Join(root, "clone")
+	worktree := filepath.Join(root, "worktree")
+
+	gitDir := filepath.Join(clone, ".git")
+	worktreeGitDir := filepath.Join(gitDir, "worktrees", "worktree")
+	require.NoError(t, os.MkdirAll(worktreeGitDir, 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(clone, "sub"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(worktree, "sub"), 0o755))
+
+	config := "[core]\n\tbare = false\n[remote \"origin\"]\n\turl = " + originURL + "\n\tfetch = +refs/heads/*:refs/remotes/origin/*\n"
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "config"), []byte(config), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(worktreeGitDir, "commondir"), []byte("../..\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: "+worktreeGitDir+"\n"), 0o644))
+
+	return clone, worktree
+}
+
+func TestEnvRepo(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+	clone, worktree := createTestGitRepo(t, "git@github.com:Owner/proj.git")
+
+	tests := []testcase{
+		{
+			name:            "01_projEnvCreate",
+			args:            envCreateTestCmd(dbName, "proj"),
+			expectActionErr: false,
+		},
+		{
+			name:            "02_projVarCreate",
+			args:            varCreateTestCmd(dbName, "proj", "X", "proj"),
+			expectActionErr: false,
+		},
+		{
+			name: "03_repoAddFromPath",
+			args: new(testCmdBuilder).Strs("env", "repo", "add").
+				EnvName("proj").Strs("--path", filepath.Join(clone, "sub")).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "04_repoAddDuplicateURL",
+			args: new(testCmdBuilder).Strs("env", "repo", "add").
+				EnvName("proj").Strs("--repo", "https://GitHub.com/Owner/proj").Finish(dbName),
+			expectActionErr: true,
+		},
+		{
+			name: "05_repoAddNotARepo",
+			args: new(testCmdBuilder).Strs("env", "repo", "add").
+				EnvName("proj").Strs("--path", t.TempDir()).Finish(dbName),
+			expectActionErr: true,
+		},
+		{
+			name: "06_envList",
+			args: new(testCmdBuilder).Strs("env", "list").
+				Tz().Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "07_matchWorktree",
+			args: new(testCmdBuilder).Strs("env", "match").
+				Strs("--path", filepath.Join(worktree, "sub")).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "08_chdirWorktree",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "/other", "--new", worktree).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "09_repoRemove",
+			args: new(testCmdBuilder).Strs("env", "repo", "remove").
+				Strs("--repo", "ssh://git@github.com/Owner/proj.git").Confirm(false).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "10_matchWorktree",
+			args: new(testCmdBuilder).Strs("env", "match").
+				Strs("--path", filepath.Join(worktree, "sub")).Finish(dbName),
+			expectActionErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenTest(t, tt, updateGolden)
+		})
+	}
+}
@@ -47,6 +47,7 @@ func TestExec(t *testing.T) {
 		Value:       "value_from_enventory_env",
 		Enabled:     true,
 		Completions: nil,
+		Scope:       nil,
 	})
 	require.NoError(err)
 
@@ -83,3 +84,59 @@ func TestExec(t *testing.T) {
 	os.Unsetenv("VAR_WITH_VALUES_COMPLETIONS")
 	os.Unsetenv("VAR_WITH_VALUES_DESCRIPTIONS_COMPLETIONS")
 }
+
+//nolint:paralleltest // the exec command sets env vars with os.Setenv
+func TestExecScoped(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping exec test on windows - no /bin/bash")
+	}
+
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	tests := []testcase{
+		{
+			name:            "01_envCreate",
+			args:            envCreateTestCmd(dbName, envName01),
+			expectActionErr: false,
+		},
+		{
+			name: "02_varCreateBashScoped",
+			args: new(testCmdBuilder).Strs("var", "create").
+				EnvName(envName01).Name("BASH_TOKEN").Strs("--value", "for_bash", "--scope", "bash").
+				ZeroTimes().Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "03_varCreateGhScoped",
+			args: new(testCmdBuilder).Strs("var", "create").
+				EnvName(envName01).Name("GH_TOKEN").Strs("--value", "for_gh", "--scope", "gh").
+				ZeroTimes().Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "04_exec",
+			// can't use testCmdBuilder because its Finish method appends --db-path after the --
+			args: []string{
+				"exec",
+				"--db-path", dbName,
+				"--env", envName01,
+				"--",
+				"/bin/bash", "--noprofile", "--norc", "--restricted",
+				"-c", "echo -n bash=$BASH_TOKEN gh=$GH_TOKEN",
+			},
+			expectActionErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenTest(t, tt, updateGolden)
+		})
+	}
+
+	// cleanup!
+	os.Unsetenv("BASH_TOKEN")
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"go.bbkane.com/enventory/cli"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/metadata"
+)
+
+// The shell dialect tests run the same cases for each shell, so their
+// behavior can't drift apart. zsh has its own, more thorough tests.
+
+func dialectTestShells() []string {
+	return []string{"bash", "fish", "nu", "pwsh"}
+}
+
+func TestShellInit(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	for _, shell := range dialectTestShells() {
+		t.Run(shell, func(t *testing.T) {
+			tt := testcase{
+				name:            "01_init",
+				args:            []string{"shell", shell, "init", "--db-path", "/tmp/enventory.db"},
+				expectActionErr: false,
+			}
+			t.Run(tt.name, func(t *testing.T) {
+				goldenTest(t, tt, updateGolden)
+			})
+		})
+	}
+}
+
+func TestShellExport(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	for _, shell := range dialectTestShells() {
+		t.Run(shell, func(t *testing.T) {
+			dbName := createTempDB(t)
+
+			tests := []testcase{
+				{
+					name:            "01_envCreate",
+					args:            envCreateTestCmd(dbName, envName01),
+					expectActionErr: false,
+				},
+				{
+					name:            "02_varCreate",
+					args:            varCreateTestCmd(dbName, envName01, varName01, "it's a value"),
+					expectActionErr: false,
+				},
+				{
+					name: "03_export",
+					args: new(testCmdBuilder).Strs("shell", shell, "export").
+						EnvName(envName01).Finish(dbName),
+					expectActionErr: false,
+				},
+				{
+					name: "04_unexport",
+					args: new(testCmdBuilder).Strs("shell", shell, "unexport").
+						EnvName(envName01).Finish(dbName),
+					expectActionErr: false,
+				},
+				{
+					name: "05_exportNoEnvNoProblem",
+					args: new(testCmdBuilder).Strs("shell", shell, "export").
+						EnvName("non-existent-env").Strs("--no-env-no-problem", "true").
+						Finish(dbName),
+					expectActionErr: false,
+				},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					goldenTest(t, tt, updateGolden)
+				})
+			}
+		})
+	}
+}
+
+func TestShellChdir(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(map[string]string{
+		"ov1": "ov1val",
+		"ov2": "ov2val",
+	}))
+
+	for _, shell := range dialectTestShells() {
+		t.Run(shell, func(t *testing.T) {
+			dbName := createTempDB(t)
+
+			tests := []testcase{
+				{
+					name:            "01_oldEnvCreate",
+					args:            envCreateTestCmd(dbName, "old"),
+					expectActionErr: false,
+				},
+				{
+					name:            "02_oldVarCreate",
+					args:            varCreateTestCmd(dbName, "old", "ov1", "ov1val"),
+					expectActionErr: false,
+				},
+				{
+					name:            "03_oldVarCreateRemoved",
+					args:            varCreateTestCmd(dbName, "old", "ov2", "ov2val"),
+					expectActionErr: false,
+				},
+				{
+					name:            "04_newEnvCreate",
+					args:            envCreateTestCmd(dbName, "new"),
+					expectActionErr: false,
+				},
+				{
+					name:            "05_newVarCreate",
+					args:            varCreateTestCmd(dbName, "new", "nv1", "nv1val"),
+					expectActionErr: false,
+				},
+				{
+					name:            "06_newVarCreateSameName",
+					args:            varCreateTestCmd(dbName, "new", "ov1", "ov1val-in-new-env"),
+					expectActionErr: false,
+				},
+				{
+					name: "07_chdir",
+					args: new(testCmdBuilder).Strs("shell", shell, "chdir").
+						Strs("--old", "old", "--new", "new").Finish(dbName),
+					expectActionErr: false,
+				},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					warg.GoldenTest(
+						t,
+						warg.GoldenTestArgs{
+							App:             buildApp(),
+							UpdateGolden:    updateGolden,
+							ExpectActionErr: tt.expectActionErr,
+							Args:            tt.args,
+						},
+						warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+						warg.ParseWithMetadata(md),
+					)
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"go.bbkane.com/enventory/cli"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/metadata"
+)
+
+func TestShellPrompt(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	// a exported X=x
+	exportedState := `{"X":{"env":"a","fp":"2d711642b726b044"}}`
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+		// shellEnv is the environment of the shell running the command
+		shellEnv map[string]string
+	}{
+		{
+			name:            "01_aEnvCreate",
+			args:            envCreateTestCmd(dbName, "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "02_aVarCreate",
+			args:            varCreateTestCmd(dbName, "a", "X", "x"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "03_promptNoEnv",
+			args:            []string{"shell", "prompt"},
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "04_prompt",
+			args:            new(testCmdBuilder).Strs("shell", "prompt", "--check-db", "true").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        map[string]string{"X": "x", "ENVENTORY_EXPORTED": exportedState},
+		},
+		{
+			name:            "05_promptShellDrift",
+			args:            []string{"shell", "prompt"},
+			expectActionErr: false,
+			shellEnv:        map[string]string{"X": "changed", "ENVENTORY_EXPORTED": exportedState},
+		},
+		{
+			name:            "06_promptShellDriftZsh",
+			args:            []string{"shell", "prompt", "--format", "zsh"},
+			expectActionErr: false,
+			shellEnv:        map[string]string{"ENVENTORY_EXPORTED": exportedState},
+		},
+		{
+			name: "07_varUpdate",
+			args: new(testCmdBuilder).Strs("var", "update").
+				EnvName("a").Name("X").Strs("--value", "y").Confirm(false).Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "08_promptDBDrift",
+			args:            new(testCmdBuilder).Strs("shell", "prompt", "--check-db", "true").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        map[string]string{"X": "x", "ENVENTORY_EXPORTED": exportedState},
+		},
+		{
+			name:            "09_promptSpawned",
+			args:            []string{"shell", "prompt", "--format", "zsh"},
+			expectActionErr: false,
+			shellEnv:        map[string]string{"ENVENTORY_SPAWNED": "work,~/proj"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(tt.shellEnv))
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             buildApp(),
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithMetadata(md),
+			)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestShellSpawn(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping spawn test on windows - no /bin/sh")
+	}
+
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	tests := []testcase{
+		{
+			name:            "01_aEnvCreate",
+			args:            envCreateTestCmd(dbName, "a"),
+			expectActionErr: false,
+		},
+		{
+			name:            "02_aVarCreate",
+			args:            varCreateTestCmd(dbName, "a", "X", "a"),
+			expectActionErr: false,
+		},
+		{
+			name:            "03_bEnvCreate",
+			args:            envCreateTestCmd(dbName, "b"),
+			expectActionErr: false,
+		},
+		{
+			name:            "04_bVarCreate",
+			args:            varCreateTestCmd(dbName, "b", "X", "b"),
+			expectActionErr: false,
+		},
+		{
+			name: "05_spawn",
+			// can't use testCmdBuilder because its Finish method appends --db-path after the --
+			args: []string{
+				"shell", "spawn",
+				"--db-path", dbName,
+				"--env", "a",
+				"--env", "b",
+				"--shell", "/bin/sh",
+				"--",
+				"-c", "echo X=$X ENVENTORY_SPAWNED=$ENVENTORY_SPAWNED",
+			},
+			expectActionErr: false,
+		},
+		{
+			name: "06_spawnNotFound",
+			args: []string{
+				"shell", "spawn",
+				"--db-path", dbName,
+				"--env", "nope",
+				"--shell", "/bin/sh",
+				"--",
+				"-c", "echo should not run",
+			},
+			expectActionErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenTest(t, tt, updateGolden)
+		})
+	}
+}
@@ -189,3 +189,643 @@ func TestShellZshChdir(t *testing.T) {
 		})
 	}
 }
+
+func TestShellZshChdirShadowed(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+		// shellEnv is the environment of the shell running the command
+		shellEnv map[string]string
+	}{
+		{
+			name:            "01_aEnvCreate",
+			args:            envCreateTestCmd(dbName, "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "02_aVarCreate",
+			args:            varCreateTestCmd(dbName, "a", "X", "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "03_bEnvCreate",
+			args:            envCreateTestCmd(dbName, "b"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "04_bVarCreateX",
+			args:            varCreateTestCmd(dbName, "b", "X", "b"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "05_bVarCreateY",
+			args:            varCreateTestCmd(dbName, "b", "Y", "b"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "06_chdirNoneToA",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "none", "--new", "a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv: map[string]string{
+				"X": "default",
+			},
+		},
+		{
+			name: "07_chdirAToB",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "a", "--new", "b").Finish(dbName),
+			expectActionErr: false,
+			shellEnv: map[string]string{
+				"X":                  "a",
+				"ENVENTORY_SHADOWED": `{"X":"default"}`,
+			},
+		},
+		{
+			name: "08_chdirBToNone",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "b", "--new", "none").Finish(dbName),
+			expectActionErr: false,
+			shellEnv: map[string]string{
+				"X":                  "b",
+				"Y":                  "b",
+				"ENVENTORY_SHADOWED": `{"X":"default"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(tt.shellEnv))
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             buildApp(),
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithMetadata(md),
+			)
+		})
+	}
+}
+
+func TestShellZshExported(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	// a exported X and Z, then Z was deleted from a
+	exportedState := `{"X":{"env":"a","fp":"ca978112ca1bbdca"},"Z":{"env":"a","fp":"ca978112ca1bbdca"}}`
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+		// shellEnv is the environment of the shell running the command
+		shellEnv map[string]string
+	}{
+		{
+			name:            "01_aEnvCreate",
+			args:            envCreateTestCmd(dbName, "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "02_aVarCreate",
+			args:            varCreateTestCmd(dbName, "a", "X", "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "03_exportUntracked",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "export").
+				EnvName("a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "04_exportUnsetsStale",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "export").
+				EnvName("a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv: map[string]string{
+				"X":                  "a",
+				"Z":                  "a",
+				"ENVENTORY_EXPORTED": exportedState,
+			},
+		},
+		{
+			name: "05_unexportTracked",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "unexport").
+				EnvName("a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv: map[string]string{
+				"X":                  "a",
+				"Z":                  "a",
+				"ENVENTORY_EXPORTED": exportedState,
+			},
+		},
+		{
+			name: "06_chdirTracked",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "a", "--new", "none").Finish(dbName),
+			expectActionErr: false,
+			shellEnv: map[string]string{
+				"X":                  "a",
+				"Z":                  "a",
+				"ENVENTORY_EXPORTED": exportedState,
+			},
+		},
+		{
+			// after unexporting everything, X was set by hand. Leaving a
+			// must not unset it.
+			name: "07_chdirAfterUnexportKeepsOwnVar",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "a", "--new", "none").Finish(dbName),
+			expectActionErr: false,
+			shellEnv: map[string]string{
+				"X":                  "mine",
+				"ENVENTORY_EXPORTED": `{}`,
+			},
+		},
+		{
+			// the shell entered a through a mapping that's since been
+			// removed, so the old directory no longer maps to a. The recorded
+			// dir envs still say to leave it.
+			name: "08_chdirLeavesRecordedDirEnvs",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "unmapped", "--new", "none").Finish(dbName),
+			expectActionErr: false,
+			shellEnv: map[string]string{
+				"X":                  "a",
+				"ENVENTORY_EXPORTED": `{"X":{"env":"a","fp":"ca978112ca1bbdca"}}`,
+				"ENVENTORY_DIR_ENVS": `["a"]`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(tt.shellEnv))
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             buildApp(),
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithMetadata(md),
+			)
+		})
+	}
+}
+
+func TestShellZshStack(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	// the shell is in directory a with b pushed on top
+	pushedEnv := map[string]string{
+		"X":                  "b",
+		"Y":                  "b",
+		"ENVENTORY_EXPORTED": `{"X":{"env":"b","fp":"3e23e8160039594a"},"Y":{"env":"b","fp":"3e23e8160039594a"}}`,
+		"ENVENTORY_STACK":    `["b"]`,
+	}
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+		// shellEnv is the environment of the shell running the command
+		shellEnv map[string]string
+	}{
+		{
+			name:            "01_aEnvCreate",
+			args:            envCreateTestCmd(dbName, "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "02_aVarCreate",
+			args:            varCreateTestCmd(dbName, "a", "X", "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "03_bEnvCreate",
+			args:            envCreateTestCmd(dbName, "b"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "04_bVarCreateX",
+			args:            varCreateTestCmd(dbName, "b", "X", "b"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "05_bVarCreateY",
+			args:            varCreateTestCmd(dbName, "b", "Y", "b"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "06_push",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "push").
+				EnvName("b").Strs("--dir", "a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv: map[string]string{
+				"X":                  "a",
+				"ENVENTORY_EXPORTED": `{"X":{"env":"a","fp":"ca978112ca1bbdca"}}`,
+			},
+		},
+		{
+			name: "07_pushNotFound",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "push").
+				EnvName("nope").Strs("--dir", "a").Finish(dbName),
+			expectActionErr: true,
+			shellEnv:        nil,
+		},
+		{
+			name:            "08_stack",
+			args:            []string{"shell", "zsh", "stack"},
+			expectActionErr: false,
+			shellEnv:        map[string]string{"ENVENTORY_STACK": `["a","b"]`},
+		},
+		{
+			name: "09_chdirKeepsStack",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "a", "--new", "none").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        pushedEnv,
+		},
+		{
+			name: "10_pop",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "pop").
+				Strs("--dir", "a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        pushedEnv,
+		},
+		{
+			name: "11_popEmpty",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "pop").
+				Strs("--dir", "a").Finish(dbName),
+			expectActionErr: true,
+			shellEnv:        nil,
+		},
+		// b is pushed over c, exported with export-env, and popped. Popping
+		// restores c's record of X along with its value, so unexporting c
+		// unsets X
+		{
+			name:            "12_cEnvCreate",
+			args:            envCreateTestCmd(dbName, "c"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "13_cVarCreateX",
+			args:            varCreateTestCmd(dbName, "c", "X", "c"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "14_pushOverExported",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "push").
+				EnvName("b").Strs("--dir", "none").Finish(dbName),
+			expectActionErr: false,
+			shellEnv: map[string]string{
+				"X":                  "c",
+				"ENVENTORY_EXPORTED": `{"X":{"env":"c","fp":"2e7d2c03a9507ae2"}}`,
+			},
+		},
+		{
+			name: "15_popOverExported",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "pop").
+				Strs("--dir", "none").Finish(dbName),
+			expectActionErr: false,
+			shellEnv: map[string]string{
+				"X":                  "b",
+				"Y":                  "b",
+				"ENVENTORY_EXPORTED": `{"X":{"env":"b","fp":"3e23e8160039594a","shadowed":{"env":"c","fp":"2e7d2c03a9507ae2"}},"Y":{"env":"b","fp":"3e23e8160039594a"}}`,
+				"ENVENTORY_SHADOWED": `{"X":"c"}`,
+				"ENVENTORY_STACK":    `["b"]`,
+			},
+		},
+		{
+			name: "16_unexportAfterPop",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "unexport").
+				EnvName("c").Finish(dbName),
+			expectActionErr: false,
+			shellEnv: map[string]string{
+				"X":                  "c",
+				"ENVENTORY_EXPORTED": `{"X":{"env":"c","fp":"2e7d2c03a9507ae2"}}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(tt.shellEnv))
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             buildApp(),
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithMetadata(md),
+			)
+		})
+	}
+}
+
+func TestShellZshTTL(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	// a was exported with a TTL over the shell's own X, and expires at
+	// 2025-01-01T00:15:00Z
+	exportedEnv := map[string]string{
+		"X":                  "secret",
+		"Y":                  "y",
+		"ENVENTORY_EXPORTED": `{"X":{"env":"a","fp":"2bb80d537b1da3e3"},"Y":{"env":"a","fp":"a1fce4363854ff88"}}`,
+		"ENVENTORY_SHADOWED": `{"X":"mine"}`,
+		"ENVENTORY_EXPIRES":  `{"a":1735690500}`,
+		"ENVENTORY_DEADLINE": "1735690500",
+	}
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+		// shellEnv is the environment of the shell running the command
+		shellEnv map[string]string
+	}{
+		{
+			name:            "01_aEnvCreate",
+			args:            envCreateTestCmd(dbName, "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "02_aVarCreateX",
+			args:            varCreateTestCmd(dbName, "a", "X", "secret"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "03_aVarCreateY",
+			args:            varCreateTestCmd(dbName, "a", "Y", "y"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "04_exportTTL",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "export").EnvName("a").
+				Strs("--ttl", "15m", "--now", "2025-01-01T00:00:00Z").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        map[string]string{"X": "mine"},
+		},
+		{
+			name:            "05_expireBeforeDeadline",
+			args:            []string{"shell", "zsh", "expire", "--now", "2025-01-01T00:10:00Z"},
+			expectActionErr: false,
+			shellEnv:        exportedEnv,
+		},
+		{
+			name:            "06_expireAfterDeadline",
+			args:            []string{"shell", "zsh", "expire", "--now", "2025-01-01T00:20:00Z"},
+			expectActionErr: false,
+			shellEnv:        exportedEnv,
+		},
+		{
+			name: "07_unexportForgetsDeadline",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "unexport").
+				EnvName("a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        exportedEnv,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(tt.shellEnv))
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             buildApp(),
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithMetadata(md),
+			)
+		})
+	}
+}
+
+func TestShellZshChdirHierarchical(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+		// shellEnv is the environment of the shell running the command
+		shellEnv map[string]string
+	}{
+		{
+			name:            "01_repoEnvCreate",
+			args:            envCreateTestCmd(dbName, "/repo"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "02_repoVarCreateA",
+			args:            varCreateTestCmd(dbName, "/repo", "A", "repo"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "03_repoVarCreateB",
+			args:            varCreateTestCmd(dbName, "/repo", "B", "repo"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "04_apiEnvCreate",
+			args:            envCreateTestCmd(dbName, "/repo/services/api"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "05_apiVarCreateB",
+			args:            varCreateTestCmd(dbName, "/repo/services/api", "B", "api"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "06_apiVarCreateC",
+			args:            varCreateTestCmd(dbName, "/repo/services/api", "C", "api"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "07_chdirOtherToApi",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "/other", "--new", "/repo/services/api", "--hierarchical", "true").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "08_chdirRepoToApi",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "/repo", "--new", "/repo/services/api", "--hierarchical", "true").Finish(dbName),
+			expectActionErr: false,
+			shellEnv: map[string]string{
+				"A": "repo",
+				"B": "repo",
+			},
+		},
+		{
+			name: "09_chdirApiToRepoNotHierarchical",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "/repo/services/api", "--new", "/repo").Finish(dbName),
+			expectActionErr: false,
+			shellEnv: map[string]string{
+				"B": "api",
+				"C": "api",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(tt.shellEnv))
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             buildApp(),
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithMetadata(md),
+			)
+		})
+	}
+}
+
+func TestShellZshReload(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	// the shell is in directory a and exported b by hand. Since then, X and Z
+	// were updated and W was deleted.
+	exportedEnv := map[string]string{
+		"W":                  "w",
+		"X":                  "old",
+		"Y":                  "y",
+		"Z":                  "b",
+		"ENVENTORY_EXPORTED": `{"W":{"env":"a","fp":"50e721e49c013f00"},"X":{"env":"a","fp":"cba06b5736faf67e"},"Y":{"env":"a","fp":"a1fce4363854ff88"},"Z":{"env":"b","fp":"3e23e8160039594a"}}`,
+	}
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+		// shellEnv is the environment of the shell running the command
+		shellEnv map[string]string
+	}{
+		{
+			name:            "01_aEnvCreate",
+			args:            envCreateTestCmd(dbName, "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "02_aVarCreateX",
+			args:            varCreateTestCmd(dbName, "a", "X", "new"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "03_aVarCreateY",
+			args:            varCreateTestCmd(dbName, "a", "Y", "y"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "04_bEnvCreate",
+			args:            envCreateTestCmd(dbName, "b"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "05_bVarCreateZ",
+			args:            varCreateTestCmd(dbName, "b", "Z", "b2"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "06_reload",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "reload").
+				Strs("--dir", "a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        exportedEnv,
+		},
+		{
+			name:            "07_initAutoReload",
+			args:            []string{"shell", "zsh", "init", "--auto-reload", "true", "--db-path", "/tmp/enventory.db"},
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(tt.shellEnv))
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             buildApp(),
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithMetadata(md),
+			)
+		})
+	}
+}
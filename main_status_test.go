@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"go.bbkane.com/enventory/cli"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/metadata"
+)
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	// the shell is in directory a. STALE was exported as "old" before the
+	// database changed, and OVERRIDDEN was changed by hand.
+	shellEnv := map[string]string{
+		"OK":                 "ok",
+		"OVERRIDDEN":         "mine",
+		"REF":                "shared",
+		"STALE":              "old",
+		"ENVENTORY_EXPORTED": `{"OK":{"env":"a","fp":"2689367b205c16ce"},"OVERRIDDEN":{"env":"a","fp":"6cdfa0bc82ed2573"},"REF":{"env":"a","fp":"a4d26868017c0ccf"},"STALE":{"env":"a","fp":"cba06b5736faf67e"}}`,
+	}
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+		// shellEnv is the environment of the shell running the command
+		shellEnv map[string]string
+	}{
+		{
+			name:            "01_aEnvCreate",
+			args:            envCreateTestCmd(dbName, "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "02_bEnvCreate",
+			args:            envCreateTestCmd(dbName, "b"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "03_varCreateOK",
+			args:            varCreateTestCmd(dbName, "a", "OK", "ok"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "04_varCreateMissing",
+			args:            varCreateTestCmd(dbName, "a", "MISSING", "missing"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "05_varCreateOverridden",
+			args:            varCreateTestCmd(dbName, "a", "OVERRIDDEN", "overridden"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "06_varCreateStale",
+			args:            varCreateTestCmd(dbName, "a", "STALE", "new"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "07_varCreateDisabled",
+			args: new(testCmdBuilder).Strs("var", "create").
+				EnvName("a").Name("DISABLED").Strs("--value", "disabled").
+				ZeroTimes().Enabled(false).Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "08_varCreateScoped",
+			args: new(testCmdBuilder).Strs("var", "create").
+				EnvName("a").Name("SCOPED").Strs("--value", "scoped", "--scope", "gh").
+				ZeroTimes().Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "09_varCreateShared",
+			args:            varCreateTestCmd(dbName, "b", "SHARED", "shared"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "10_varRefCreate",
+			args: new(testCmdBuilder).Strs("var", "ref", "create").
+				EnvName("a").Name("REF").ZeroTimes().
+				Strs("--ref-env", "b", "--ref-var", "SHARED").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "11_status",
+			args: new(testCmdBuilder).Strs("status", "--dir", "a").
+				Mask(false).Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        shellEnv,
+		},
+		{
+			name: "12_statusMasked",
+			args: new(testCmdBuilder).Strs("status", "--dir", "a").
+				Mask(true).Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        shellEnv,
+		},
+		{
+			name: "13_statusNoEnvs",
+			args: new(testCmdBuilder).Strs("status", "--dir", "none").
+				Mask(false).Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        shellEnv,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(tt.shellEnv))
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             buildApp(),
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithMetadata(md),
+			)
+		})
+	}
+}
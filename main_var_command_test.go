@@ -0,0 +1,162 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.bbkane.com/enventory/cli"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/metadata"
+)
+
+//nolint:paralleltest // commands run in this process's environment, set with t.Setenv
+func TestVarCommand(t *testing.T) {
+	// COUNTER's command prints how many times it ran. The count file is
+	// passed through the environment so env trust prints the same command
+	// every run.
+	t.Setenv("ENVENTORY_TEST_COUNT_FILE", filepath.Join(t.TempDir(), "count"))
+	counterCommand := `echo x >> "$ENVENTORY_TEST_COUNT_FILE"; wc -l < "$ENVENTORY_TEST_COUNT_FILE" | tr -d ' '`
+
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+	// command vars only run once their env is trusted
+	shellEnv := map[string]string{"ENVENTORY_TRUST_DIR": t.TempDir()}
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+	}{
+		{
+			name:            "01_envCreate",
+			args:            envCreateTestCmd(dbName, "a"),
+			expectActionErr: false,
+		},
+		{
+			name: "02_varCreateToken",
+			args: new(testCmdBuilder).Strs("var", "create").
+				EnvName("a").Name("TOKEN").Strs("--kind", "command", "--value", "echo tok-1").
+				ZeroTimes().Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "03_varCreateCounter",
+			args: new(testCmdBuilder).Strs("var", "create").
+				EnvName("a").Name("COUNTER").Strs("--kind", "command", "--value", counterCommand, "--cache-ttl", "1h").
+				ZeroTimes().Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "04_varCreateBroken",
+			args: new(testCmdBuilder).Strs("var", "create").
+				EnvName("a").Name("BROKEN").Strs("--kind", "command", "--value", "echo oops >&2; exit 3").
+				ZeroTimes().Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name:            "05_varCreatePlain",
+			args:            varCreateTestCmd(dbName, "a", "PLAIN", "plain"),
+			expectActionErr: false,
+		},
+		{
+			name: "06_varShowNotCached",
+			args: new(testCmdBuilder).Strs("var", "show").
+				EnvName("a").Name("TOKEN").Tz().Mask(false).
+				Strs("--now", "2025-01-01T00:00:00Z").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "07_zshChdirUntrusted",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "none", "--new", "a", "--now", "2025-01-01T00:00:00Z").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name:            "08_envTrust",
+			args:            new(testCmdBuilder).Strs("env", "trust").Name("a").Confirm(false).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "09_zshChdirRuns",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "none", "--new", "a", "--now", "2025-01-01T00:00:00Z").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "10_zshChdirCached",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "none", "--new", "a", "--now", "2025-01-01T00:10:00Z").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "11_varShowCached",
+			args: new(testCmdBuilder).Strs("var", "show").
+				EnvName("a").Name("TOKEN").Tz().Mask(false).
+				Strs("--now", "2025-01-01T00:10:00Z").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "12_zshChdirExpired",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "none", "--new", "a", "--now", "2025-01-01T02:00:00Z").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "13_varShowExpired",
+			args: new(testCmdBuilder).Strs("var", "show").
+				EnvName("a").Name("TOKEN").Tz().Mask(false).
+				Strs("--now", "2025-01-01T03:00:00Z").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "14_varUpdateCommand",
+			args: new(testCmdBuilder).Strs("var", "update").
+				EnvName("a").Name("TOKEN").Strs("--value", "echo tok-2").
+				Confirm(false).Strs("--update-time", "0001-01-01T00:00:00Z").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "15_varShowEdited",
+			args: new(testCmdBuilder).Strs("var", "show").
+				EnvName("a").Name("TOKEN").Tz().Mask(false).
+				Strs("--now", "2025-01-01T03:00:00Z").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "16_zshExportEditedUntrusted",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "export").
+				EnvName("a").Strs("--now", "2025-01-01T03:00:00Z").Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name:            "17_envTrustEdited",
+			args:            new(testCmdBuilder).Strs("env", "trust").Name("a").Confirm(false).Finish(dbName),
+			expectActionErr: false,
+		},
+		{
+			name: "18_zshExportEdited",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "export").
+				EnvName("a").Strs("--now", "2025-01-01T03:00:00Z").Finish(dbName),
+			expectActionErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(shellEnv))
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             buildApp(),
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithMetadata(md),
+			)
+		})
+	}
+}
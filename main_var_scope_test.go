@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"go.bbkane.com/enventory/cli"
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/metadata"
+)
+
+func TestVarScope(t *testing.T) {
+	t.Parallel()
+	updateGolden := os.Getenv("ENVENTORY_TEST_UPDATE_GOLDEN") != ""
+
+	dbName := createTempDB(t)
+
+	// a exported X and recorded its scoped GITHUB_TOKEN. The shell has its own
+	// GITHUB_TOKEN.
+	exportedEnv := map[string]string{
+		"X":                  "x",
+		"GITHUB_TOKEN":       "mine",
+		"ENVENTORY_EXPORTED": `{"GITHUB_TOKEN":{"env":"a","fp":"1a7674eb4ee78df7","scope":"gh"},"X":{"env":"a","fp":"2d711642b726b044"}}`,
+	}
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+		// shellEnv is the environment of the shell running the command
+		shellEnv map[string]string
+	}{
+		{
+			name:            "01_aEnvCreate",
+			args:            envCreateTestCmd(dbName, "a"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "02_varCreateScoped",
+			args: new(testCmdBuilder).Strs("var", "create").
+				EnvName("a").Name("GITHUB_TOKEN").Strs("--value", "tok", "--scope", "gh").
+				ZeroTimes().Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "03_varCreate",
+			args:            varCreateTestCmd(dbName, "a", "X", "x"),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "04_varCreateBadScope",
+			args: new(testCmdBuilder).Strs("var", "create").
+				EnvName("a").Name("Y").Strs("--value", "y", "--scope", "gh;rm").
+				ZeroTimes().Finish(dbName),
+			expectActionErr: true,
+			shellEnv:        nil,
+		},
+		{
+			name: "05_varShow",
+			args: new(testCmdBuilder).Strs("var", "show").
+				EnvName("a").Name("GITHUB_TOKEN").Tz().Mask(false).Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name: "06_exportSkipsScoped",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "export").
+				EnvName("a").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        map[string]string{"GITHUB_TOKEN": "mine"},
+		},
+		{
+			name: "07_chdirKeepsOwnValue",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "chdir").
+				Strs("--old", "a", "--new", "none").Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        exportedEnv,
+		},
+		{
+			name: "08_scopedCommands",
+			args: new(testCmdBuilder).Strs("shell", "zsh", "scoped-commands").
+				Finish(dbName),
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+		{
+			name:            "09_initScopedCommands",
+			args:            []string{"shell", "zsh", "init", "--scoped-commands", "true", "--db-path", "/tmp/enventory.db"},
+			expectActionErr: false,
+			shellEnv:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.New(cli.CustomLookupEnvFuncKey{}, cli.LookupMap(tt.shellEnv))
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             buildApp(),
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithMetadata(md),
+			)
+		})
+	}
+}
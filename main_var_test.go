@@ -148,6 +148,7 @@ func TestEnvUniqueNames(t *testing.T) {
 			UpdateTime:  time.Time{},
 			Enabled:     true,
 			Completions: nil,
+			Scope:       nil,
 		})
 		return err
 	}
@@ -161,6 +162,7 @@ func TestEnvUniqueNames(t *testing.T) {
 			Value:       nil,
 			Enabled:     nil,
 			Completions: nil,
+			Scope:       nil,
 		})
 		return err
 	}
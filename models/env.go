@@ -2,11 +2,62 @@ package models
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"errors"
+	"maps"
+	"slices"
 	"time"
 )
 
+// -- Alias
+
+var ErrAliasNotFound = errors.New("alias not found")
+
+type AliasKind string
+
+const (
+	// AliasKindAlias expands to Value, like `alias k='kubectl'`
+	AliasKindAlias AliasKind = "alias"
+	// AliasKindFunction runs Value as the body of a shell function
+	AliasKindFunction AliasKind = "function"
+)
+
+// Alias is a shell alias or function defined while its env is active
+type Alias struct {
+	EnvName    string
+	Name       string
+	Kind       AliasKind
+	Value      string
+	Comment    string
+	CreateTime time.Time
+	UpdateTime time.Time
+	Enabled    bool
+}
+
+type AliasCreateArgs struct {
+	EnvName    string
+	Name       string
+	Kind       AliasKind
+	Value      string
+	Comment    string
+	CreateTime time.Time
+	UpdateTime time.Time
+	Enabled    bool
+}
+
+type AliasUpdateArgs struct {
+	Comment    *string
+	CreateTime *time.Time
+	EnvName    *string
+	Kind       *AliasKind
+	Name       *string
+	UpdateTime *time.Time
+	Value      *string
+	Enabled    *bool
+}
+
 // -- Env
 
 var ErrEnvNotFound = errors.New("env not found")
@@ -17,6 +68,29 @@ type Env struct {
 	CreateTime time.Time
 	UpdateTime time.Time
 	Enabled    bool
+	// Repos are the keys of the git repos the env is activated in
+	Repos []string
+	// OnEnter and OnLeave are commands the shell runs when it enters or
+	// leaves the env
+	OnEnter string
+	OnLeave string
+}
+
+// TrustHash identifies the code an env runs: its hooks and the commands of
+// its command vars, by var name. Editing any of them invalidates an earlier
+// trust.
+func TrustHash(onEnter string, onLeave string, commands map[string]string) string {
+	h := sha256.New()
+	h.Write([]byte(onEnter + "\x00" + onLeave))
+	for _, name := range slices.Sorted(maps.Keys(commands)) {
+		h.Write([]byte("\x00" + name + "\x00" + commands[name]))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HasHooks reports whether the env runs a command on entry or on leave
+func (e Env) HasHooks() bool {
+	return e.OnEnter != "" || e.OnLeave != ""
 }
 
 type EnvCreateArgs struct {
@@ -37,12 +111,80 @@ type EnvUpdateArgs struct {
 	Name       *string
 	UpdateTime *time.Time
 	Enabled    *bool
+	OnEnter    *string
+	OnLeave    *string
+}
+
+// -- EnvInclude
+
+var ErrEnvIncludeNotFound = errors.New("env include not found")
+
+// ErrEnvIncludeCycle means an env would (directly or indirectly) include itself
+var ErrEnvIncludeCycle = errors.New("env include cycle")
+
+// -- EnvPath
+
+var ErrEnvPathNotFound = errors.New("env path not found")
+
+// EnvPath is a directory an env is activated for
+type EnvPath struct {
+	EnvName string
+	Path    string
+}
+
+// -- EnvPattern
+
+var ErrEnvPatternNotFound = errors.New("env pattern not found")
+
+type EnvPatternKind string
+
+const (
+	// EnvPatternKindGlob matches like filepath.Match
+	EnvPatternKindGlob EnvPatternKind = "glob"
+	// EnvPatternKindRegex must match the whole path
+	EnvPatternKindRegex EnvPatternKind = "regex"
+)
+
+type EnvPatternMatchArgs struct {
+	// Paths spell the same directory, like its canonical path and its env
+	// name relative to the home directory (~/proj). A pattern matches if it
+	// matches any of them, so patterns can start with ~/.
+	Paths []string
+	// CaseInsensitive ignores case when matching
+	CaseInsensitive bool
+}
+
+type EnvPattern struct {
+	EnvName string
+	Pattern string
+	Kind    EnvPatternKind
+}
+
+// -- EnvRepo
+
+var ErrEnvRepoNotFound = errors.New("env repo not found")
+
+// EnvRepo is a git repo an env is activated in, wherever it's cloned
+type EnvRepo struct {
+	EnvName string
+	// Repo is the normalized URL of the repo's origin remote
+	Repo string
 }
 
 // -- Var
 
 var ErrVarNotFound = errors.New("local var not found")
 
+type VarKind string
+
+const (
+	// VarKindValue vars export Value
+	VarKindValue VarKind = "value"
+	// VarKindCommand vars export the output of running Value as a shell
+	// command
+	VarKindCommand VarKind = "command"
+)
+
 type Var struct {
 	EnvName     string
 	Name        string
@@ -52,6 +194,12 @@ type Var struct {
 	Value       string
 	Enabled     bool
 	Completions []string
+	// Scope lists the commands the var is passed to instead of being
+	// exported into the shell. It's empty for ordinary vars.
+	Scope []string
+	Kind  VarKind
+	// CacheTTL is how long a command var's output is reused
+	CacheTTL time.Duration
 }
 
 type VarCreateArgs struct {
@@ -63,6 +211,9 @@ type VarCreateArgs struct {
 	Value       string
 	Enabled     bool
 	Completions []string
+	Scope       []string
+	Kind        VarKind
+	CacheTTL    time.Duration
 }
 
 type VarUpdateArgs struct {
@@ -74,6 +225,22 @@ type VarUpdateArgs struct {
 	Value       *string
 	Enabled     *bool
 	Completions *[]string
+	Scope       *[]string
+	Kind        *VarKind
+	CacheTTL    *time.Duration
+}
+
+// -- VarCache
+
+var ErrVarCacheNotFound = errors.New("var cache not found")
+
+// VarCache is the saved output of a command var's command
+type VarCache struct {
+	// Command produced Value. The cache doesn't apply once the var's command
+	// is edited.
+	Command   string
+	Value     string
+	CacheTime time.Time
 }
 
 // -- VarRef
@@ -116,9 +283,28 @@ type VarRefUpdateArgs struct {
 // -- EnvExportable
 
 type EnvExportable struct {
-	Name    string
+	Name string
+	// Type is "var" or "var_ref"
+	Type    string
 	Enabled bool
 	Value   string
+	// Scope is the scope of the var, or of the var a ref points to
+	Scope []string
+	// EnvName owns the var or ref. It's an included env if this is inherited.
+	EnvName string
+	// Kind and CacheTTL are those of the var, or of the var a ref points to
+	Kind     VarKind
+	CacheTTL time.Duration
+	// VarEnvName and VarName identify the var providing the value, which is
+	// the ref's var for refs
+	VarEnvName string
+	VarName    string
+}
+
+// IsTemplate reports whether Value is a template with placeholders to
+// expand. A command var's Value is a shell command, so it isn't.
+func (e EnvExportable) IsTemplate() bool {
+	return e.Kind != VarKindCommand
 }
 
 // -- interface
@@ -131,14 +317,45 @@ type DBTX interface {
 }
 
 type Service interface {
+	AliasCreate(ctx context.Context, args AliasCreateArgs) (*Alias, error)
+	AliasDelete(ctx context.Context, envName string, name string) error
+	AliasList(ctx context.Context, envName string) ([]Alias, error)
+	AliasShow(ctx context.Context, envName string, name string) (*Alias, error)
+	AliasUpdate(ctx context.Context, envName string, name string, args AliasUpdateArgs) error
+
 	EnvCreate(ctx context.Context, args EnvCreateArgs) (*Env, error)
 	EnvDelete(ctx context.Context, name string) error
 	EnvList(ctx context.Context, args EnvListArgs) ([]Env, error)
 	EnvUpdate(ctx context.Context, name string, args EnvUpdateArgs) error
 	EnvShow(ctx context.Context, name string) (*Env, error)
 
+	// EnvExportableList lists the env's vars and refs, followed by those it
+	// inherits from its includes. Templates aren't expanded.
 	EnvExportableList(ctx context.Context, envName string) ([]EnvExportable, error)
 
+	EnvIncludeCreate(ctx context.Context, envName string, includeEnvName string) error
+	EnvIncludeDelete(ctx context.Context, envName string, includeEnvName string) error
+	EnvIncludeList(ctx context.Context, envName string) ([]string, error)
+
+	EnvPathAdd(ctx context.Context, args EnvPath) error
+	EnvPathRemove(ctx context.Context, path string) error
+	// EnvPathFind returns the name of the env path is bound to
+	EnvPathFind(ctx context.Context, path string) (string, error)
+	EnvPathList(ctx context.Context, envName string) ([]EnvPath, error)
+	EnvPathListAll(ctx context.Context) ([]EnvPath, error)
+
+	EnvPatternCreate(ctx context.Context, args EnvPattern) error
+	EnvPatternDelete(ctx context.Context, envName string, pattern string) error
+	EnvPatternList(ctx context.Context, envName string) ([]EnvPattern, error)
+	// EnvPatternMatch returns the patterns matching args.Paths, from lowest
+	// to highest precedence
+	EnvPatternMatch(ctx context.Context, args EnvPatternMatchArgs) ([]EnvPattern, error)
+
+	EnvRepoAdd(ctx context.Context, args EnvRepo) error
+	EnvRepoRemove(ctx context.Context, repo string) error
+	// EnvRepoFind returns the name of the env repo is bound to
+	EnvRepoFind(ctx context.Context, repo string) (string, error)
+
 	// TODO: should envName be its own parameter?
 	VarCreate(ctx context.Context, args VarCreateArgs) (*Var, error)
 	VarDelete(ctx context.Context, envName string, name string) error
@@ -146,6 +363,9 @@ type Service interface {
 	VarUpdate(ctx context.Context, envName string, name string, args VarUpdateArgs) error
 	VarShow(ctx context.Context, envName string, name string) (*Var, []VarRef, error)
 
+	VarCacheShow(ctx context.Context, envName string, name string) (*VarCache, error)
+	VarCacheSet(ctx context.Context, envName string, name string, cache VarCache) error
+
 	VarRefCreate(ctx context.Context, args VarRefCreateArgs) (*VarRef, error)
 	VarRefDelete(ctx context.Context, envName string, name string) error
 	VarRefList(ctx context.Context, envName string) ([]VarRef, []Var, error)
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Var values are templates: ${NAME} is replaced by the value of the var or
+// ref named NAME in the same exported set, and ${env:NAME} by the process's
+// env var NAME. $${ is a literal ${.
+
+// ErrVarTemplateInvalid means a value has a malformed placeholder
+var ErrVarTemplateInvalid = errors.New("invalid var template")
+
+// ErrVarReferenceUnknown means a placeholder names a var that isn't exported
+// with the var using it
+var ErrVarReferenceUnknown = errors.New("unknown var reference")
+
+// ErrVarReferenceCycle means a var's value (directly or indirectly)
+// references itself
+var ErrVarReferenceCycle = errors.New("var reference cycle")
+
+// ErrVarTemplateEnvUnset means an ${env:NAME} placeholder names an env var
+// the process doesn't have
+var ErrVarTemplateEnvUnset = errors.New("env var not set")
+
+const templateEnvPrefix = "env:"
+
+// templatePart is literal text or a placeholder
+type templatePart struct {
+	Literal string
+	// Name is the placeholder's var name, without the env: prefix
+	Name string
+	// Env placeholders name a process env var
+	Env bool
+}
+
+func parseTemplate(template string) ([]templatePart, error) {
+	parts := []templatePart{}
+	literal := strings.Builder{}
+	rest := template
+	for {
+		i := strings.Index(rest, "${")
+		if i == -1 {
+			literal.WriteString(rest)
+			break
+		}
+		if i > 0 && rest[i-1] == '$' {
+			literal.WriteString(rest[:i-1] + "${")
+			rest = rest[i+2:]
+			continue
+		}
+		literal.WriteString(rest[:i])
+		end := strings.IndexByte(rest[i:], '}')
+		if end == -1 {
+			return nil, fmt.Errorf("%w: unterminated placeholder: %s", ErrVarTemplateInvalid, rest[i:])
+		}
+		name := rest[i+2 : i+end]
+		env := strings.HasPrefix(name, templateEnvPrefix)
+		name = strings.TrimPrefix(name, templateEnvPrefix)
+		if name == "" {
+			return nil, fmt.Errorf("%w: empty placeholder: %s", ErrVarTemplateInvalid, rest[i:i+end+1])
+		}
+		if literal.Len() > 0 {
+			parts = append(parts, templatePart{Literal: literal.String(), Name: "", Env: false})
+			literal.Reset()
+		}
+		parts = append(parts, templatePart{Literal: "", Name: name, Env: env})
+		rest = rest[i+end+1:]
+	}
+	if literal.Len() > 0 {
+		parts = append(parts, templatePart{Literal: literal.String(), Name: "", Env: false})
+	}
+	return parts, nil
+}
+
+// ValidateTemplate returns an error if template has a malformed placeholder
+func ValidateTemplate(template string) error {
+	_, err := parseTemplate(template)
+	return err
+}
+
+// TemplateReferences returns the names of the vars template's placeholders
+// reference, in order and without duplicates. ${env:NAME} placeholders aren't
+// included.
+func TemplateReferences(template string) ([]string, error) {
+	parts, err := parseTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, p := range parts {
+		if p.Name != "" && !p.Env && !slices.Contains(names, p.Name) {
+			names = append(names, p.Name)
+		}
+	}
+	return names, nil
+}
+
+// ExpandTemplate replaces template's placeholders with the values lookupVar
+// and lookupEnv return
+func ExpandTemplate(template string, lookupVar func(string) (string, bool), lookupEnv func(string) (string, bool)) (string, error) {
+	parts, err := parseTemplate(template)
+	if err != nil {
+		return "", err
+	}
+	expanded := strings.Builder{}
+	for _, p := range parts {
+		switch {
+		case p.Name == "":
+			expanded.WriteString(p.Literal)
+		case p.Env:
+			value, exists := lookupEnv(p.Name)
+			if !exists {
+				return "", fmt.Errorf("%w: %s", ErrVarTemplateEnvUnset, p.Name)
+			}
+			expanded.WriteString(value)
+		default:
+			value, exists := lookupVar(p.Name)
+			if !exists {
+				return "", fmt.Errorf("%w: %s", ErrVarReferenceUnknown, p.Name)
+			}
+			expanded.WriteString(value)
+		}
+	}
+	return expanded.String(), nil
+}
@@ -94,3 +94,22 @@ func StringSlicePtrToJSONPtr(s *[]string) *string {
 	jsonStr := StringSliceToJSON(*s)
 	return &jsonStr
 }
+
+// DurationToSeconds converts a time.Duration to whole seconds
+func DurationToSeconds(d time.Duration) int64 {
+	return int64(d / time.Second)
+}
+
+// SecondsToDuration converts whole seconds to a time.Duration
+func SecondsToDuration(s int64) time.Duration {
+	return time.Duration(s) * time.Second
+}
+
+// DurationPtrToSecondsPtr converts a *time.Duration to *int64 seconds
+func DurationPtrToSecondsPtr(d *time.Duration) *int64 {
+	if d == nil {
+		return nil
+	}
+	s := DurationToSeconds(*d)
+	return &s
+}
@@ -112,6 +112,121 @@ func NewTracedService(tracer trace.Tracer, envService Service) *TracedService {
 
 // -- Env
 
+func (t *TracedService) AliasCreate(ctx context.Context, args AliasCreateArgs) (*Alias, error) {
+	ctx, span := t.tracer.Start(
+		ctx,
+		"AliasCreate",
+		trace.WithAttributes(
+			attribute.String("args.EnvName", args.EnvName),
+			attribute.String("args.Name", args.Name),
+			attribute.String("args.Kind", string(args.Kind)),
+			attribute.String("args.Value", "<redacted>"), // can be sensitive
+			attribute.String("args.Comment", args.Comment),
+			attribute.String("args.CreateTime", TimeToString(args.CreateTime)),
+			attribute.String("args.UpdateTime", TimeToString(args.UpdateTime)),
+			attribute.Bool("args.Enabled", args.Enabled),
+		),
+	)
+	defer span.End()
+
+	alias, err := t.Service.AliasCreate(ctx, args)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return alias, err
+}
+
+func (t *TracedService) AliasDelete(ctx context.Context, envName string, name string) error {
+	ctx, span := t.tracer.Start(
+		ctx,
+		"AliasDelete",
+		trace.WithAttributes(
+			attribute.String("envName", envName),
+			attribute.String("name", name),
+		),
+	)
+	defer span.End()
+
+	err := t.Service.AliasDelete(ctx, envName, name)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (t *TracedService) AliasList(ctx context.Context, envName string) ([]Alias, error) {
+	ctx, span := t.tracer.Start(
+		ctx,
+		"AliasList",
+		trace.WithAttributes(attribute.String("envName", envName)),
+	)
+	defer span.End()
+
+	aliases, err := t.Service.AliasList(ctx, envName)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return aliases, err
+}
+
+func (t *TracedService) AliasShow(ctx context.Context, envName string, name string) (*Alias, error) {
+	ctx, span := t.tracer.Start(
+		ctx,
+		"AliasShow",
+		trace.WithAttributes(
+			attribute.String("envName", envName),
+			attribute.String("name", name),
+		),
+	)
+	defer span.End()
+
+	alias, err := t.Service.AliasShow(ctx, envName, name)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return alias, err
+}
+
+func (t *TracedService) AliasUpdate(ctx context.Context, envName string, name string, args AliasUpdateArgs) error {
+	argsValue := "<nil>"
+	if args.Value != nil {
+		argsValue = "<redacted>" // can be sensitive
+	}
+	ctx, span := t.tracer.Start(
+		ctx,
+		"AliasUpdate",
+		trace.WithAttributes(
+			attribute.String("envName", envName),
+			attribute.String("name", name),
+			attribute.String("args.EnvName", ptrToString(args.EnvName)),
+			attribute.String("args.Name", ptrToString(args.Name)),
+			attribute.String("args.Kind", ptrToString(args.Kind)),
+			attribute.String("args.Value", argsValue), // can be sensitive
+			attribute.String("args.Comment", ptrToString(args.Comment)),
+			attribute.String("args.CreateTime", ptrToString(TimePtrToStringPtr(args.CreateTime))),
+			attribute.String("args.UpdateTime", ptrToString(TimePtrToStringPtr(args.UpdateTime))),
+			attribute.String("args.Enabled", ptrToString(args.Enabled)),
+		),
+	)
+	defer span.End()
+
+	err := t.Service.AliasUpdate(ctx, envName, name, args)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
 func (t *TracedService) EnvCreate(ctx context.Context, args EnvCreateArgs) (*Env, error) {
 	ctx, span := t.tracer.Start(
 		ctx,
@@ -178,6 +293,237 @@ func (t *TracedService) EnvExportableList(ctx context.Context, envName string) (
 	return items, err
 }
 
+func (t *TracedService) EnvIncludeCreate(ctx context.Context, envName string, includeEnvName string) error {
+	ctx, span := t.tracer.Start(ctx, "EnvIncludeCreate", trace.WithAttributes(
+		attribute.String("envName", envName),
+		attribute.String("includeEnvName", includeEnvName),
+	))
+	defer span.End()
+
+	err := t.Service.EnvIncludeCreate(ctx, envName, includeEnvName)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (t *TracedService) EnvIncludeDelete(ctx context.Context, envName string, includeEnvName string) error {
+	ctx, span := t.tracer.Start(ctx, "EnvIncludeDelete", trace.WithAttributes(
+		attribute.String("envName", envName),
+		attribute.String("includeEnvName", includeEnvName),
+	))
+	defer span.End()
+
+	err := t.Service.EnvIncludeDelete(ctx, envName, includeEnvName)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (t *TracedService) EnvIncludeList(ctx context.Context, envName string) ([]string, error) {
+	ctx, span := t.tracer.Start(ctx, "EnvIncludeList", trace.WithAttributes(
+		attribute.String("envName", envName),
+	))
+	defer span.End()
+
+	includes, err := t.Service.EnvIncludeList(ctx, envName)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return includes, err
+}
+
+func (t *TracedService) EnvPathAdd(ctx context.Context, args EnvPath) error {
+	ctx, span := t.tracer.Start(ctx, "EnvPathAdd", trace.WithAttributes(
+		attribute.String("args.EnvName", args.EnvName),
+		attribute.String("args.Path", args.Path),
+	))
+	defer span.End()
+
+	err := t.Service.EnvPathAdd(ctx, args)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (t *TracedService) EnvPathRemove(ctx context.Context, path string) error {
+	ctx, span := t.tracer.Start(ctx, "EnvPathRemove", trace.WithAttributes(
+		attribute.String("path", path),
+	))
+	defer span.End()
+
+	err := t.Service.EnvPathRemove(ctx, path)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (t *TracedService) EnvPathFind(ctx context.Context, path string) (string, error) {
+	ctx, span := t.tracer.Start(ctx, "EnvPathFind", trace.WithAttributes(
+		attribute.String("path", path),
+	))
+	defer span.End()
+
+	envName, err := t.Service.EnvPathFind(ctx, path)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return envName, err
+}
+
+func (t *TracedService) EnvPathList(ctx context.Context, envName string) ([]EnvPath, error) {
+	ctx, span := t.tracer.Start(ctx, "EnvPathList", trace.WithAttributes(
+		attribute.String("envName", envName),
+	))
+	defer span.End()
+
+	paths, err := t.Service.EnvPathList(ctx, envName)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return paths, err
+}
+
+func (t *TracedService) EnvPathListAll(ctx context.Context) ([]EnvPath, error) {
+	ctx, span := t.tracer.Start(ctx, "EnvPathListAll")
+	defer span.End()
+
+	paths, err := t.Service.EnvPathListAll(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return paths, err
+}
+
+func (t *TracedService) EnvPatternCreate(ctx context.Context, args EnvPattern) error {
+	ctx, span := t.tracer.Start(ctx, "EnvPatternCreate", trace.WithAttributes(
+		attribute.String("args.EnvName", args.EnvName),
+		attribute.String("args.Pattern", args.Pattern),
+		attribute.String("args.Kind", string(args.Kind)),
+	))
+	defer span.End()
+
+	err := t.Service.EnvPatternCreate(ctx, args)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (t *TracedService) EnvPatternDelete(ctx context.Context, envName string, pattern string) error {
+	ctx, span := t.tracer.Start(ctx, "EnvPatternDelete", trace.WithAttributes(
+		attribute.String("envName", envName),
+		attribute.String("pattern", pattern),
+	))
+	defer span.End()
+
+	err := t.Service.EnvPatternDelete(ctx, envName, pattern)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (t *TracedService) EnvPatternList(ctx context.Context, envName string) ([]EnvPattern, error) {
+	ctx, span := t.tracer.Start(ctx, "EnvPatternList", trace.WithAttributes(
+		attribute.String("envName", envName),
+	))
+	defer span.End()
+
+	patterns, err := t.Service.EnvPatternList(ctx, envName)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return patterns, err
+}
+
+func (t *TracedService) EnvPatternMatch(ctx context.Context, args EnvPatternMatchArgs) ([]EnvPattern, error) {
+	ctx, span := t.tracer.Start(ctx, "EnvPatternMatch", trace.WithAttributes(
+		attribute.StringSlice("paths", args.Paths),
+		attribute.Bool("case_insensitive", args.CaseInsensitive),
+	))
+	defer span.End()
+
+	patterns, err := t.Service.EnvPatternMatch(ctx, args)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return patterns, err
+}
+
+func (t *TracedService) EnvRepoAdd(ctx context.Context, args EnvRepo) error {
+	ctx, span := t.tracer.Start(ctx, "EnvRepoAdd", trace.WithAttributes(
+		attribute.String("args.EnvName", args.EnvName),
+		attribute.String("args.Repo", args.Repo),
+	))
+	defer span.End()
+
+	err := t.Service.EnvRepoAdd(ctx, args)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (t *TracedService) EnvRepoRemove(ctx context.Context, repo string) error {
+	ctx, span := t.tracer.Start(ctx, "EnvRepoRemove", trace.WithAttributes(
+		attribute.String("repo", repo),
+	))
+	defer span.End()
+
+	err := t.Service.EnvRepoRemove(ctx, repo)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (t *TracedService) EnvRepoFind(ctx context.Context, repo string) (string, error) {
+	ctx, span := t.tracer.Start(ctx, "EnvRepoFind", trace.WithAttributes(
+		attribute.String("repo", repo),
+	))
+	defer span.End()
+
+	envName, err := t.Service.EnvRepoFind(ctx, repo)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return envName, err
+}
+
 func (t *TracedService) EnvUpdate(ctx context.Context, name string, args EnvUpdateArgs) error {
 	ctx, span := t.tracer.Start(
 		ctx,
@@ -189,6 +535,8 @@ func (t *TracedService) EnvUpdate(ctx context.Context, name string, args EnvUpda
 			attribute.String("args.CreateTime", ptrToString(TimePtrToStringPtr(args.CreateTime))),
 			attribute.String("args.UpdateTime", ptrToString(TimePtrToStringPtr(args.UpdateTime))),
 			attribute.String("args.Enabled", ptrToString(args.Enabled)),
+			attribute.String("args.OnEnter", ptrToString(args.OnEnter)),
+			attribute.String("args.OnLeave", ptrToString(args.OnLeave)),
 		),
 	)
 	defer span.End()
@@ -230,6 +578,9 @@ func (t *TracedService) VarCreate(ctx context.Context, args VarCreateArgs) (*Var
 			attribute.String("args.UpdateTime", TimeToString(args.UpdateTime)),
 			attribute.Bool("args.Enabled", args.Enabled),
 			attribute.Int("args.Completions.Len", len(args.Completions)),
+			attribute.StringSlice("args.Scope", args.Scope),
+			attribute.String("args.Kind", string(args.Kind)),
+			attribute.String("args.CacheTTL", args.CacheTTL.String()),
 		),
 	)
 	defer span.End()
@@ -289,6 +640,10 @@ func (t *TracedService) VarUpdate(ctx context.Context, envName string, name stri
 	if args.Completions != nil {
 		argsCompletionsLen = fmt.Sprintf("%d", len(*args.Completions))
 	}
+	argsScope := "<nil>"
+	if args.Scope != nil {
+		argsScope = fmt.Sprintf("%v", *args.Scope)
+	}
 	ctx, span := t.tracer.Start(
 		ctx,
 		"VarUpdate",
@@ -302,6 +657,9 @@ func (t *TracedService) VarUpdate(ctx context.Context, envName string, name stri
 			attribute.String("args.UpdateTime", ptrToString(TimePtrToStringPtr(args.UpdateTime))),
 			attribute.String("args.Enabled", ptrToString(args.Enabled)),
 			attribute.String("args.Completions.Len", argsCompletionsLen),
+			attribute.String("args.Scope", argsScope),
+			attribute.String("args.Kind", ptrToString(args.Kind)),
+			attribute.String("args.CacheTTL", ptrToString(args.CacheTTL)),
 		),
 	)
 	defer span.End()
@@ -335,6 +693,51 @@ func (t *TracedService) VarShow(ctx context.Context, envName string, name string
 	return variable, refs, err
 }
 
+// -- VarCache
+
+func (t *TracedService) VarCacheShow(ctx context.Context, envName string, name string) (*VarCache, error) {
+	ctx, span := t.tracer.Start(
+		ctx,
+		"VarCacheShow",
+		trace.WithAttributes(
+			attribute.String("envName", envName),
+			attribute.String("name", name),
+		),
+	)
+	defer span.End()
+
+	cache, err := t.Service.VarCacheShow(ctx, envName, name)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return cache, err
+}
+
+func (t *TracedService) VarCacheSet(ctx context.Context, envName string, name string, cache VarCache) error {
+	ctx, span := t.tracer.Start(
+		ctx,
+		"VarCacheSet",
+		trace.WithAttributes(
+			attribute.String("envName", envName),
+			attribute.String("name", name),
+			attribute.String("cache.Command", cache.Command),
+			attribute.String("cache.Value", "<redacted>"), // can be sensitive
+			attribute.String("cache.CacheTime", TimeToString(cache.CacheTime)),
+		),
+	)
+	defer span.End()
+
+	err := t.Service.VarCacheSet(ctx, envName, name, cache)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
 // -- VarRef
 
 func (t *TracedService) VarRefCreate(ctx context.Context, args VarRefCreateArgs) (*VarRef, error) {